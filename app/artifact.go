@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/storage"
+)
+
+var (
+	ErrArtifactNotFound = errors.New("artifact not found")
+	ErrArtifactExpired  = errors.New("artifact expired")
+)
+
+// expiredArtifactsBatch is the number of expired artifacts deleted at once by the cleanup
+const expiredArtifactsBatch = 100
+
+// ArtifactApp tracks the result files of the users. The jobs write their files to a work directory,
+// the files are then moved to the storage and are only reachable through the artifact of their owner.
+type ArtifactApp struct {
+	repository    database.ArtifactRepository
+	storage       storage.Storage
+	workDir       string        // Directory of the files being written
+	ttl           time.Duration // Time the files can be downloaded after they are stored
+	presignExpiry time.Duration // Lifetime of the presigned download URLs
+}
+
+// NewArtifactApp creates a new ArtifactApp instance
+func NewArtifactApp(repository database.ArtifactRepository, store storage.Storage, workDir string, ttl time.Duration, presignExpiry time.Duration) *ArtifactApp {
+	if repository == nil {
+		log.Fatal("artifactRepository cannot be nil")
+	}
+	if store == nil {
+		log.Fatal("storage cannot be nil")
+	}
+	if workDir == "" {
+		log.Fatal("artifacts work directory cannot be empty")
+	}
+
+	return &ArtifactApp{
+		repository:    repository,
+		storage:       store,
+		workDir:       workDir,
+		ttl:           ttl,
+		presignExpiry: presignExpiry,
+	}
+}
+
+// WorkDir returns the directory of the files being written
+func (a *ArtifactApp) WorkDir() string {
+	return a.workDir
+}
+
+// NewOutputPath returns a new path of the work directory to write a result file in the format
+func (a *ArtifactApp) NewOutputPath(format string) string {
+	return filepath.Join(a.workDir, uuid.New().String()+"_channels_followers."+format)
+}
+
+// Register moves the file written for the job to the storage and records it as an artifact
+// of the owner of the job
+func (a *ArtifactApp) Register(job *database.Job, filePath string, format string) (*database.Artifact, error) {
+	artifact := database.NewArtifact(job.UserID, job.ID, "", "channels_followers."+format, format, a.ttl)
+	// Every artifact has its own key, so deleting an expired file never touches a newer export
+	artifact.StorageKey = artifact.ID + "/" + artifact.FileName
+	if err := a.storage.Save(context.Background(), artifact.StorageKey, filePath); err != nil {
+		return nil, err
+	}
+	if err := a.repository.SaveArtifact(artifact); err != nil {
+		if err := a.storage.Delete(context.Background(), artifact.StorageKey); err != nil {
+			log.Printf("Failed to delete the file of artifact %s: %v", artifact.ID, err)
+		}
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// Get returns the artifact only if it belongs to the given user. Artifacts of other users are reported
+// as not found so their IDs cannot be probed.
+func (a *ArtifactApp) Get(userID string, artifactID string) (*database.Artifact, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+	artifact, err := a.repository.GetArtifactByID(artifactID)
+	if err != nil || artifact.UserID != userID {
+		return nil, ErrArtifactNotFound
+	}
+	if artifact.IsExpired() {
+		return nil, ErrArtifactExpired
+	}
+	return artifact, nil
+}
+
+// Open streams the file of the artifact
+func (a *ArtifactApp) Open(artifact *database.Artifact) (io.ReadCloser, error) {
+	file, err := a.storage.Open(context.Background(), artifact.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrArtifactExpired
+	}
+	return file, err
+}
+
+// DownloadURL returns a temporary URL of the storage downloading the file of the artifact,
+// storage.ErrPresignUnsupported when the file has to be streamed with Open
+func (a *ArtifactApp) DownloadURL(artifact *database.Artifact) (string, error) {
+	// The URL never outlives the artifact
+	expiry := min(a.presignExpiry, time.Until(artifact.ExpiresAt))
+	return a.storage.PresignedURL(context.Background(), artifact.StorageKey, artifact.FileName, expiry)
+}
+
+// DeleteExpired removes the files of the expired artifacts and their records
+func (a *ArtifactApp) DeleteExpired() (int, error) {
+	deleted := 0
+	for {
+		artifacts, err := a.repository.GetExpiredArtifacts(expiredArtifactsBatch)
+		if err != nil {
+			return deleted, err
+		}
+		for _, artifact := range artifacts {
+			// The record is kept when its file can't be deleted, so the next run retries it
+			if err := a.storage.Delete(context.Background(), artifact.StorageKey); err != nil {
+				return deleted, err
+			}
+			if err := a.repository.DeleteArtifact(artifact.ID); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(artifacts) < expiredArtifactsBatch {
+			return deleted, nil
+		}
+	}
+}
+
+// findByJob returns the live artifact of the job in the format, nil when there is none
+func (a *ArtifactApp) findByJob(job *database.Job, format string) *database.Artifact {
+	artifact, err := a.repository.GetArtifactByJob(job.ID, format)
+	if err != nil || artifact.UserID != job.UserID || artifact.IsExpired() {
+		return nil
+	}
+	if exists, err := a.storage.Exists(context.Background(), artifact.StorageKey); err != nil || !exists {
+		return nil
+	}
+	return artifact
+}
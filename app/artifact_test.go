@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/storage"
+)
+
+// memoryArtifactRepository keeps the artifacts in memory
+type memoryArtifactRepository struct {
+	mutex     sync.Mutex
+	artifacts map[string]*database.Artifact
+}
+
+var _ database.ArtifactRepository = (*memoryArtifactRepository)(nil)
+
+func newMemoryArtifactRepository() *memoryArtifactRepository {
+	return &memoryArtifactRepository{artifacts: make(map[string]*database.Artifact)}
+}
+
+func (mr *memoryArtifactRepository) SaveArtifact(artifact *database.Artifact) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	stored := *artifact
+	mr.artifacts[artifact.ID] = &stored
+	return nil
+}
+
+func (mr *memoryArtifactRepository) GetArtifactByID(artifactID string) (*database.Artifact, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	artifact, ok := mr.artifacts[artifactID]
+	if !ok {
+		return nil, errors.New("artifact not found")
+	}
+	stored := *artifact
+	return &stored, nil
+}
+
+func (mr *memoryArtifactRepository) GetArtifactByJob(jobID string, format string) (*database.Artifact, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	var newest *database.Artifact
+	for _, artifact := range mr.artifacts {
+		if artifact.JobID == jobID && artifact.Format == format && (newest == nil || artifact.CreatedAt.After(newest.CreatedAt)) {
+			newest = artifact
+		}
+	}
+	if newest == nil {
+		return nil, errors.New("artifact not found")
+	}
+	stored := *newest
+	return &stored, nil
+}
+
+func (mr *memoryArtifactRepository) GetExpiredArtifacts(limit int) ([]*database.Artifact, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	var expired []*database.Artifact
+	for _, artifact := range mr.artifacts {
+		if artifact.IsExpired() && len(expired) < limit {
+			stored := *artifact
+			expired = append(expired, &stored)
+		}
+	}
+	return expired, nil
+}
+
+func (mr *memoryArtifactRepository) DeleteArtifact(artifactID string) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	delete(mr.artifacts, artifactID)
+	return nil
+}
+
+// expire moves the expiry of the stored artifact to the past
+func (mr *memoryArtifactRepository) expire(artifactID string) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	mr.artifacts[artifactID].ExpiresAt = time.Now().Add(-time.Minute)
+}
+
+// newTestArtifactApp creates an ArtifactApp on a local storage in a temporary directory
+func newTestArtifactApp(t *testing.T) (*ArtifactApp, *memoryArtifactRepository, string) {
+	t.Helper()
+	dir := t.TempDir()
+	workDir := filepath.Join(dir, "results")
+	store, err := storage.NewLocalStorage(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repository := newMemoryArtifactRepository()
+	return NewArtifactApp(repository, store, workDir, time.Hour, 15*time.Minute), repository, dir
+}
+
+// registerTestArtifact writes a result file for the job of the user and registers it
+func registerTestArtifact(t *testing.T, artifacts *ArtifactApp, userID string, content string) *database.Artifact {
+	t.Helper()
+	outputFile := artifacts.NewOutputPath("csv")
+	if err := os.WriteFile(outputFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	job := &database.Job{ID: "job-" + userID, UserID: userID}
+	artifact, err := artifacts.Register(job, outputFile, "csv")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return artifact
+}
+
+func TestArtifactAppOwnership(t *testing.T) {
+	artifacts, _, _ := newTestArtifactApp(t)
+	artifact := registerTestArtifact(t, artifacts, "alice", "alice results")
+
+	got, err := artifacts.Get("alice", artifact.ID)
+	if err != nil {
+		t.Fatalf("Get by the owner: %v", err)
+	}
+	file, err := artifacts.Open(got)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if string(data) != "alice results" {
+		t.Errorf("Open read %q, want the file of the owner", data)
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		artifactID string
+		wantErr    error
+	}{
+		{"other user", "mallory", artifact.ID, ErrArtifactNotFound},
+		{"no user", "", artifact.ID, ErrInvalidUserID},
+		{"unknown artifact", "alice", "missing", ErrArtifactNotFound},
+		{"path as ID", "alice", "../.env", ErrArtifactNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := artifacts.Get(tt.userID, tt.artifactID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get(%q, %q) error = %v, want %v", tt.userID, tt.artifactID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArtifactAppRejectsTraversalKeys(t *testing.T) {
+	artifacts, _, dir := newTestArtifactApp(t)
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("JWT_SECRET=secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are never built from the requests, a tampered record must still stay inside the storage
+	for _, key := range []string{"../.env", "artifact/../../.env", filepath.Join(dir, ".env"), `..\.env`} {
+		artifact := &database.Artifact{ID: "tampered", UserID: "alice", StorageKey: key, ExpiresAt: time.Now().Add(time.Hour)}
+		if file, err := artifacts.Open(artifact); !errors.Is(err, storage.ErrInvalidKey) {
+			if file != nil {
+				file.Close()
+			}
+			t.Errorf("Open with key %q error = %v, want storage.ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestArtifactAppExpiry(t *testing.T) {
+	artifacts, repository, dir := newTestArtifactApp(t)
+	artifact := registerTestArtifact(t, artifacts, "alice", "alice results")
+	kept := registerTestArtifact(t, artifacts, "bob", "bob results")
+
+	repository.expire(artifact.ID)
+	if _, err := artifacts.Get("alice", artifact.ID); !errors.Is(err, ErrArtifactExpired) {
+		t.Fatalf("Get of an expired artifact error = %v, want ErrArtifactExpired", err)
+	}
+
+	deleted, err := artifacts.DeleteExpired()
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpired = %d, %v, want 1", deleted, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "results", filepath.FromSlash(artifact.StorageKey))); !os.IsNotExist(err) {
+		t.Errorf("the file of the expired artifact was kept: %v", err)
+	}
+	if _, err := artifacts.Get("bob", kept.ID); err != nil {
+		t.Errorf("the live artifact was deleted: %v", err)
+	}
+}
+
+func TestArtifactAppMissingFile(t *testing.T) {
+	artifacts, _, dir := newTestArtifactApp(t)
+	artifact := registerTestArtifact(t, artifacts, "alice", "alice results")
+	if err := os.Remove(filepath.Join(dir, "results", filepath.FromSlash(artifact.StorageKey))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := artifacts.Open(artifact); !errors.Is(err, ErrArtifactExpired) {
+		t.Fatalf("Open of a deleted file error = %v, want ErrArtifactExpired", err)
+	}
+	if found := artifacts.findByJob(&database.Job{ID: "job-alice", UserID: "alice"}, "csv"); found != nil {
+		t.Errorf("findByJob returned the artifact of a deleted file")
+	}
+}
+
+// newS3ArtifactApp creates an ArtifactApp on the S3 compatible service of S3_TEST_ENDPOINT (MinIO for example).
+// The test is skipped unless S3_TEST_ENDPOINT is set.
+func newS3ArtifactApp(t *testing.T) (*ArtifactApp, *memoryArtifactRepository, storage.Storage) {
+	t.Helper()
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "results-test"
+	}
+	store, err := storage.NewS3Storage(storage.Config{
+		Backend:     storage.BackendS3,
+		S3Endpoint:  endpoint,
+		S3AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		S3Bucket:    bucket,
+		S3Region:    os.Getenv("S3_TEST_REGION"),
+		S3UseSSL:    os.Getenv("S3_TEST_USE_SSL") == "true",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	repository := newMemoryArtifactRepository()
+	return NewArtifactApp(repository, store, t.TempDir(), time.Hour, 15*time.Minute), repository, store
+}
+
+func TestArtifactAppDeleteExpiredS3(t *testing.T) {
+	artifacts, repository, store := newS3ArtifactApp(t)
+	ctx := context.Background()
+	artifact := registerTestArtifact(t, artifacts, "alice", "alice results")
+	kept := registerTestArtifact(t, artifacts, "bob", "bob results")
+	t.Cleanup(func() {
+		for _, key := range []string{artifact.StorageKey, kept.StorageKey} {
+			if err := store.Delete(context.Background(), key); err != nil {
+				t.Logf("Failed to delete %s: %v", key, err)
+			}
+		}
+	})
+
+	repository.expire(artifact.ID)
+	deleted, err := artifacts.DeleteExpired()
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteExpired = %d, %v, want 1", deleted, err)
+	}
+
+	tests := []struct {
+		name       string
+		artifact   *database.Artifact
+		wantExists bool
+	}{
+		{name: "expired", artifact: artifact, wantExists: false},
+		{name: "live", artifact: kept, wantExists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if exists, err := store.Exists(ctx, tt.artifact.StorageKey); err != nil || exists != tt.wantExists {
+				t.Errorf("Exists = %v, %v, want %v", exists, err, tt.wantExists)
+			}
+			_, err := repository.GetArtifactByID(tt.artifact.ID)
+			if recorded := err == nil; recorded != tt.wantExists {
+				t.Errorf("recorded = %v, want %v", recorded, tt.wantExists)
+			}
+		})
+	}
+
+	file, err := artifacts.Open(kept)
+	if err != nil {
+		t.Fatalf("Open of the live artifact: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(data) != "bob results" {
+		t.Errorf("Open read %q, %v", data, err)
+	}
+	if downloadURL, err := artifacts.DownloadURL(kept); err != nil || downloadURL == "" {
+		t.Errorf("DownloadURL = %q, %v", downloadURL, err)
+	}
+}
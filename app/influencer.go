@@ -1,26 +1,64 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"strconv"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/database"
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+	"github.com/solrac97gr/telegram-followers-checker/ratelimit"
 	ruregistration "github.com/solrac97gr/telegram-followers-checker/ru-registration"
 )
 
+var (
+	// ErrNoExtractor is returned for links that none of the extractors can handle
+	ErrNoExtractor    = errors.New("no extractor can handle the link")
+	ErrForbiddenOwner = errors.New("analyses of other users can only be searched by admins")
+	// ErrLinkPanic is the error of the links whose processing panicked
+	ErrLinkPanic = errors.New("unexpected error processing the link")
+)
+
+// RegistrationPlatform is the platform of the registration checks for the rate limiter
+const RegistrationPlatform = "gosuslugi"
+
 // InfluencerApp orchestrates the components of the application
 type InfluencerApp struct {
 	influencersRepository database.InfluencerRepository
 	fileManager           filemanager.FileManager
-	extractors            []extractor.StatisticExtractor
+	registry              *extractor.Registry
+	limiter               *ratelimit.Limiter
+	runner                extractor.ScriptRunner
+	registrationRetry     extractor.RetryPolicy
+	extractionWorkers     int
+	registrationWorkers   int
 }
 
+// InfluencerAppOptions configures how the links are processed
+type InfluencerAppOptions struct {
+	// Limiter throttles the registration checks with the "gosuslugi" limit, it is required
+	Limiter *ratelimit.Limiter
+	// Runner runs the registration check script, it is required
+	Runner extractor.ScriptRunner
+	// RegistrationRetry is the retry policy of the registration checks
+	RegistrationRetry extractor.RetryPolicy
+	// ExtractionWorkers and RegistrationWorkers are the sizes of the worker pools of a run
+	ExtractionWorkers   int
+	RegistrationWorkers int
+}
+
+// ProgressFunc is called every time a link finishes processing.
+// It is called once with a nil result before processing starts.
+type ProgressFunc func(processed int, total int, result *LinkResult)
+
 // NewInfluencerApp creates a new App instance
-func NewInfluencerApp(influencersRepository database.InfluencerRepository, fm filemanager.FileManager, extractors ...extractor.StatisticExtractor) *InfluencerApp {
+func NewInfluencerApp(influencersRepository database.InfluencerRepository, fm filemanager.FileManager, registry *extractor.Registry, opts InfluencerAppOptions) *InfluencerApp {
 
 	if influencersRepository == nil {
 		log.Fatal("influencersRepository cannot be nil")
@@ -28,157 +66,638 @@ func NewInfluencerApp(influencersRepository database.InfluencerRepository, fm fi
 	if fm == nil {
 		log.Fatal("fileManager cannot be nil")
 	}
-	if len(extractors) == 0 {
-		log.Fatal("At least one extractor must be provided")
+	if registry == nil {
+		log.Fatal("registry cannot be nil")
+	}
+	if opts.Limiter == nil {
+		log.Fatal("limiter cannot be nil")
+	}
+	if opts.Runner == nil {
+		log.Fatal("runner cannot be nil")
+	}
+	if opts.ExtractionWorkers < 1 {
+		opts.ExtractionWorkers = 1
+	}
+	if opts.RegistrationWorkers < 1 {
+		opts.RegistrationWorkers = 1
 	}
 
 	return &InfluencerApp{
 		influencersRepository: influencersRepository,
 		fileManager:           fm,
-		extractors:            extractors,
+		registry:              registry,
+		limiter:               opts.Limiter,
+		runner:                opts.Runner,
+		registrationRetry:     opts.RegistrationRetry,
+		extractionWorkers:     opts.ExtractionWorkers,
+		registrationWorkers:   opts.RegistrationWorkers,
 	}
 }
 
-// Run processes the input file and generates the output file
-func (a *InfluencerApp) Run(userId string, inputFile string, outputFile string) [][]string {
+// RunOptions configures how the input file is read and how the output file is written
+type RunOptions struct {
+	// Enrich keeps the rows and columns of the input file in the output, the results are appended next to them
+	Enrich bool
+	// Input selects the sheets, the column and the header row of the links in spreadsheets
+	Input filemanager.ReadOptions
+	// Format is the format of the output file, see filemanager.NewResultWriter. The enrich mode writes xlsx only.
+	Format string
+}
+
+// RunOptionsFromJob returns the options stored with the job
+func RunOptionsFromJob(options database.JobOptions) RunOptions {
+	return RunOptions{
+		Enrich: options.Enrich,
+		Input: filemanager.ReadOptions{
+			Sheets:    options.Sheets,
+			AllSheets: options.AllSheets,
+			Column:    options.Column,
+			HeaderRow: options.HeaderRow,
+		},
+		Format: options.Format,
+	}
+}
+
+// JobOptions returns the options in the form they are stored with a job
+func (o RunOptions) JobOptions() database.JobOptions {
+	return database.JobOptions{
+		Enrich:    o.Enrich,
+		Sheets:    o.Input.Sheets,
+		AllSheets: o.Input.AllSheets,
+		Column:    o.Input.Column,
+		HeaderRow: o.Input.HeaderRow,
+		Format:    o.Format,
+	}
+}
+
+// validate checks the options that don't depend on the input file
+func (o RunOptions) validate() error {
+	format, err := filemanager.ParseFormat(o.Format)
+	if err != nil {
+		return err
+	}
+	if o.Enrich && format != filemanager.FormatXLSX {
+		return fmt.Errorf("%w: the enrich mode writes %s files only", filemanager.ErrInvalidOptions, filemanager.FormatXLSX)
+	}
+	return nil
+}
+
+// Run processes the input file and generates the output file.
+// onProgress is optional and receives the number of processed links as they complete.
+// When ctx is cancelled the running scripts are killed and ctx.Err() is returned.
+// The returned results always have one result per link, whatever the layout of the output file.
+func (a *InfluencerApp) Run(ctx context.Context, userId string, inputFile string, outputFile string, opts RunOptions, onProgress ProgressFunc) ([]filemanager.Result, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Enrich {
+		sheets, err := a.fileManager.ReadSheets(inputFile, opts.Input)
+		if err != nil {
+			return nil, err
+		}
+		links := make([]string, 0)
+		for _, sheet := range sheets {
+			for _, link := range sheet.Links {
+				links = append(links, link.Link)
+			}
+		}
+		results, err := a.processLinks(ctx, userId, links, sheetPosition(sheets), onProgress)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.fileManager.SaveEnrichedExcel(sheets, resultRows(results), outputFile); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
 	// Read links from input file (auto-detects file type: Excel, CSV, or text)
-	links := a.fileManager.ReadLinksFromFile(inputFile)
-	return a.processLinks(userId, links, outputFile)
+	links, err := a.fileManager.ReadLinksFromFile(inputFile, opts.Input)
+	if err != nil {
+		return nil, err
+	}
+	format, _ := filemanager.ParseFormat(opts.Format)
+	results, err := a.processLinks(ctx, userId, links, formatPosition(format), onProgress)
+	if err != nil {
+		return nil, err
+	}
+	// Save results to output file
+	if err := a.fileManager.SaveResults(results, opts.Format, outputFile); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
-// processLinks is a common method to process links regardless of input source
-func (a *InfluencerApp) processLinks(userId string, links []string, outputFile string) [][]string {
-	// Create a slice to store results in order
-	orderedResults := make([][]string, 0, len(links)+1)
-	// Add header row
-	orderedResults = append(orderedResults, []string{"Channel Name", "Followers Count", "Original Link", "Platform", "Registration Status"})
+// resultRows returns the header and the displayed row of every result
+func resultRows(results []filemanager.Result) [][]string {
+	rows := make([][]string, 0, len(results)+1)
+	rows = append(rows, filemanager.ResultHeader)
+	for _, result := range results {
+		rows = append(rows, result.Row())
+	}
+	return rows
+}
 
-	// Create a slice to store results at the correct index
-	resultsList := make([][]string, len(links))
+// ValidateInput reads the input file the way Run does and returns its errors, see filemanager.IsValidationError
+func (a *InfluencerApp) ValidateInput(inputFile string, opts RunOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.Enrich {
+		_, err := a.fileManager.ReadSheets(inputFile, opts.Input)
+		return err
+	}
+	_, err := a.fileManager.ReadLinksFromFile(inputFile, opts.Input)
+	return err
+}
 
-	// Create a mutex to protect the resultsList during concurrent writes
-	var mutex sync.Mutex
+// linkTask is a link moving through the processing pipeline
+type linkTask struct {
+	index      int
+	link       string // Link as read from the input file
+	channelKey string // Stored form of the channel key, "" when the channel is unknown
+	info       extractor.ChannelInfo
+}
 
-	// Create a WaitGroup to wait for all goroutines to finish
-	var wg sync.WaitGroup
+// processLinks is a common method to process links regardless of input source.
+// Links go through a pipeline of extraction workers feeding registration check workers,
+// the results keep the order of the links. The notes of the duplicates name the first link of their channel
+// with position.
+func (a *InfluencerApp) processLinks(ctx context.Context, userId string, links []string, position linkPosition, onProgress ProgressFunc) ([]filemanager.Result, error) {
+	// Create a slice to store results at the correct index, every worker writes its own indexes
+	resultsList := make([]filemanager.Result, len(links))
 
-	// Create a semaphore to limit concurrent registration checks
-	semaphore := make(chan struct{}, 10)
+	// Links of the same channel are processed once, their duplicates copy the result of the first one
+	firsts, duplicates := a.dedupeLinks(links)
 
-	// Process each link concurrently
-	for i, link := range links {
-		resp, err := a.influencersRepository.GetInfluencerAnalysisByLink(link)
-		if err != nil {
-			log.Printf("Error fetching analysis for %s: %v", link, err)
-		}
-		if resp != nil && err == nil {
-			log.Printf("Link %s already processed, getting from database.", link)
-			resultsList[i] = resp.ToExcelRow()
-		} else { // Find appropriate extractor for this link
-			var info extractor.ChannelInfo
-			for _, e := range a.extractors {
-				if e.CanHandle(link) {
-					info = e.Extract(link)
-					info.Platform = e.Name()
-					break
-				}
-			}
+	// Report progress every time a link is done
+	var processed int32
+	report := func(result *LinkResult) {
+		done := atomic.AddInt32(&processed, 1)
+		if onProgress != nil {
+			onProgress(int(done), len(links), result)
+		}
+	}
+	// The result of a link is written before it is reported, it is then copied to its duplicates
+	reportProgress := func(result *LinkResult) {
+		report(result)
+		for _, dup := range duplicates[result.Index] {
+			note := duplicateNote(position, result.Index)
+			resultsList[dup] = duplicateResult(resultsList[result.Index], result.Index, links[dup], note)
+			dupResult := *result
+			dupResult.Index = dup
+			dupResult.Link = links[dup]
+			dupResult.Note = note
+			report(&dupResult)
+		}
+	}
+	if onProgress != nil {
+		onProgress(0, len(links), nil)
+	}
 
-			// If no extractor found or extraction failed, use defaults
-			if info.ChannelName == "" {
-				info = extractor.ChannelInfo{
-					ChannelName:    "Unknown",
-					FollowersCount: "0",
-					OriginalLink:   link,
-					Platform:       "Unknown",
-				}
+	// Feed the indexes of the links to the extraction workers until the processing is cancelled
+	extractionTasks := make(chan int)
+	go func() {
+		defer close(extractionTasks)
+		for _, i := range firsts {
+			select {
+			case extractionTasks <- i:
+			case <-ctx.Done():
+				return
 			}
+		}
+	}()
 
-			// Skip registration status check if platform is Instagram or followers count is < 10000
-			followersCount, err := strconv.Atoi(info.FollowersCount)
-			if info.Platform == "Instagram" || (err == nil && followersCount < 10000) {
-				info.RegistrationStatus = "not applicable ⚪"
-				// Store result directly at the correct position
-				analysis := database.NewInfluencerAnalysis(
-					userId,                  // UserID
-					info.ChannelName,        // ChannelName
-					info.OriginalLink,       // Link
-					info.Platform,           // Platform
-					info.FollowersCount,     // FollowersCount
-					info.RegistrationStatus, // RegistrationStatus
-				)
-				resultsList[i] = analysis.ToExcelRow()
-				err := a.influencersRepository.SaveInfluencerAnalysis(analysis)
-				if err != nil {
-					log.Printf("Error saving analysis for %s: %v", info.OriginalLink, err)
+	registrationTasks := make(chan linkTask)
+	var extractionWg sync.WaitGroup
+	for w := 0; w < a.extractionWorkers; w++ {
+		extractionWg.Add(1)
+		go func() {
+			defer extractionWg.Done()
+			for idx := range extractionTasks {
+				task, needsRegistration := a.safeExtractLink(ctx, userId, idx, links[idx], resultsList, reportProgress)
+				if !needsRegistration {
 					continue
 				}
-				continue
+				select {
+				case registrationTasks <- task:
+				case <-ctx.Done():
+				}
 			}
+		}()
+	}
+	// The registration workers stop once every extraction is done
+	go func() {
+		extractionWg.Wait()
+		close(registrationTasks)
+	}()
 
-			// Add to WaitGroup only for links that will be processed
-			wg.Add(1)
-			go func(idx int, currentInfo extractor.ChannelInfo, linkUrl string) {
-				defer wg.Done()
-
-				// Define isRegistered channel
-				isRegistered := make(chan bool)
-
-				go func() {
-					semaphore <- struct{}{} // Acquire semaphore
-					isRegistered <- ruregistration.CheckRegistrationStatus(linkUrl, semaphore)
-					close(isRegistered)
-				}()
-
-				// Collect the result
-				currentInfo.IsRegistered = <-isRegistered
-				if currentInfo.IsRegistered {
-					currentInfo.RegistrationStatus = "registered 🟢"
-				} else {
-					currentInfo.RegistrationStatus = "not registered 🔴"
-				}
+	var registrationWg sync.WaitGroup
+	for w := 0; w < a.registrationWorkers; w++ {
+		registrationWg.Add(1)
+		go func() {
+			defer registrationWg.Done()
+			for task := range registrationTasks {
+				a.safeCheckRegistration(ctx, userId, task, resultsList, reportProgress)
+			}
+		}()
+	}
 
-				// Store result at the correct position in the resultsList
-				mutex.Lock()
-				analysis := database.NewInfluencerAnalysis(
-					userId,                         // UserID
-					currentInfo.ChannelName,        // ChannelName
-					currentInfo.OriginalLink,       // Link
-					currentInfo.Platform,           // Platform
-					currentInfo.FollowersCount,     // FollowersCount
-					currentInfo.RegistrationStatus, // RegistrationStatus
-				)
-				err := a.influencersRepository.SaveInfluencerAnalysis(analysis)
-				if err != nil {
-					log.Printf("Error saving analysis for %s: %v", info.OriginalLink, err)
-				}
-				resultsList[idx] = analysis.ToExcelRow()
-				mutex.Unlock()
-				// Avoid hitting rate limits
-				time.Sleep(1 * time.Second)
-			}(i, info, link)
+	// Wait for all workers to finish
+	registrationWg.Wait()
+
+	if ctx.Err() != nil {
+		log.Printf("Processing cancelled after %d of %d links: %v", atomic.LoadInt32(&processed), len(links), ctx.Err())
+		return nil, ctx.Err()
+	}
+
+	log.Printf("Processed %d links successfully. Preparing to save results...", len(links))
+
+	return resultsList, nil
+}
+
+// dedupeLinks groups the links by channel, links that don't name their channel are grouped by normalized link.
+// It returns the indexes of the first link of every channel and the indexes of their duplicates.
+func (a *InfluencerApp) dedupeLinks(links []string) ([]int, map[int][]int) {
+	firsts := make([]int, 0, len(links))
+	duplicates := make(map[int][]int)
+	seen := make(map[string]int, len(links))
+	for i, link := range links {
+		key := a.registry.Normalize(link)
+		if channelKey, ok := a.registry.ChannelKey(link); ok {
+			key = channelKey.String()
+		}
+		if first, ok := seen[key]; ok {
+			duplicates[first] = append(duplicates[first], i)
+			continue
 		}
+		seen[key] = i
+		firsts = append(firsts, i)
+	}
+	if len(duplicates) > 0 {
+		log.Printf("%d of %d links are duplicates, they are processed once", len(links)-len(firsts), len(links))
 	}
+	return firsts, duplicates
+}
 
-	// Wait for all goroutines to finish
-	wg.Wait()
+// linkPosition names the place of the link at index in the output
+type linkPosition func(index int) string
 
-	// Append all results in order
-	orderedResults = append(orderedResults, resultsList...)
+// formatPosition numbers the links as the rows of the spreadsheet formats, where the header is row 1,
+// or as the items of the JSON formats
+func formatPosition(format string) linkPosition {
+	if format == filemanager.FormatJSON || format == filemanager.FormatJSONL {
+		return func(index int) string {
+			return fmt.Sprintf("item %d", index+1)
+		}
+	}
+	return func(index int) string {
+		return fmt.Sprintf("row %d", index+2)
+	}
+}
 
-	log.Printf("Processed %d links successfully. Preparing to save results...", len(links))
+// sheetPosition numbers the links as the rows of the input sheets, the enriched output keeps them in place
+func sheetPosition(sheets []*filemanager.Sheet) linkPosition {
+	positions := make([]string, 0)
+	for _, sheet := range sheets {
+		for _, link := range sheet.Links {
+			position := fmt.Sprintf("row %d", link.Row+1)
+			if sheet.Name != "" {
+				position += " of sheet " + sheet.Name
+			}
+			positions = append(positions, position)
+		}
+	}
+	return func(index int) string {
+		return positions[index]
+	}
+}
 
-	log.Printf("orderedResults: %v", orderedResults)
-	// Save results to output file
-	a.fileManager.SaveResultsToExcel(orderedResults, outputFile)
+// duplicateNote is the note of the duplicates of the link at index
+func duplicateNote(position linkPosition, index int) string {
+	return "duplicate of " + position(index)
+}
+
+// setDuplicateNotes rewrites the notes of the duplicates with another numbering of the links
+func setDuplicateNotes(results []filemanager.Result, position linkPosition) {
+	for i := range results {
+		if results[i].DuplicateOf > 0 {
+			results[i].Note = duplicateNote(position, results[i].DuplicateOf-1)
+		}
+	}
+}
 
-	return orderedResults
+// duplicateResult copies the result of the first link of a channel for one of its duplicates
+func duplicateResult(result filemanager.Result, first int, link string, note string) filemanager.Result {
+	result.Link = link
+	result.DuplicateOf = first + 1
+	result.Note = note
+	return result
 }
 
-func (a *InfluencerApp) GetAllInfluencerAnalysis(pageNum, limit int) (database.AllInfluencerAnalysis, error) {
-	return a.influencersRepository.GetAllInfluencerAnalyses(pageNum, limit)
+// safeExtractLink runs extractLink in a worker. A panic fails the link instead of crashing the server,
+// the link is reported unless it already was.
+func (a *InfluencerApp) safeExtractLink(ctx context.Context, userId string, idx int, link string, resultsList []filemanager.Result, reportProgress func(*LinkResult)) (task linkTask, needsRegistration bool) {
+	reported := false
+	report := func(result *LinkResult) {
+		reported = true
+		reportProgress(result)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic extracting %s: %v\n%s", link, r, debug.Stack())
+			task, needsRegistration = linkTask{}, false
+			if !reported {
+				failPanickedLink(idx, extractor.ChannelInfo{OriginalLink: link}, r, resultsList, reportProgress)
+			}
+		}
+	}()
+	return a.extractLink(ctx, userId, idx, link, resultsList, report)
+}
+
+// safeCheckRegistration runs checkRegistration in a worker. A panic fails the link instead of crashing the server,
+// the link is reported unless it already was.
+func (a *InfluencerApp) safeCheckRegistration(ctx context.Context, userId string, task linkTask, resultsList []filemanager.Result, reportProgress func(*LinkResult)) {
+	reported := false
+	report := func(result *LinkResult) {
+		reported = true
+		reportProgress(result)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic checking the registration of %s: %v\n%s", task.link, r, debug.Stack())
+			if !reported {
+				failPanickedLink(task.index, task.info, r, resultsList, reportProgress)
+			}
+		}
+	}()
+	a.checkRegistration(ctx, userId, task, resultsList, report)
+}
+
+// failPanickedLink stores and reports the failure of a link whose processing panicked
+func failPanickedLink(idx int, info extractor.ChannelInfo, recovered any, resultsList []filemanager.Result, reportProgress func(*LinkResult)) {
+	err := fmt.Errorf("%w: %v", ErrLinkPanic, recovered)
+	resultsList[idx] = failedResult(info, err)
+	reportProgress(newFailedLinkResult(idx, info, err))
+}
+
+// extractLink gets the channel information of the link from the database or its extractor.
+// It stores the result of the link unless the registration status still has to be checked.
+func (a *InfluencerApp) extractLink(ctx context.Context, userId string, idx int, link string, resultsList []filemanager.Result, reportProgress func(*LinkResult)) (linkTask, bool) {
+	// Every link of a channel shares its analysis, links that don't name their channel are matched as is
+	key, hasKey := a.registry.ChannelKey(link)
+	if a.reuseAnalysis(userId, idx, link, key, hasKey, resultsList, reportProgress) {
+		return linkTask{}, false
+	}
+
+	// Find appropriate extractor for this link
+	info, platform, err := a.extract(ctx, link)
+
+	// The extraction was interrupted, the result must not be stored
+	if ctx.Err() != nil {
+		return linkTask{}, false
+	}
+
+	// Failed extractions are reported but never stored as analyses
+	if err != nil {
+		log.Printf("Error extracting %s: %v", link, err)
+		resultsList[idx] = failedResult(info, err)
+		reportProgress(newFailedLinkResult(idx, info, err))
+		return linkTask{}, false
+	}
+	if info.ChannelName == "" {
+		info.ChannelName = "Unknown"
+	}
+
+	// Post and video links are resolved to their channel by the extractor
+	if !hasKey && info.ChannelLink != "" {
+		if key, hasKey = a.registry.ChannelKey(info.ChannelLink); hasKey && a.reuseAnalysis(userId, idx, link, key, hasKey, resultsList, reportProgress) {
+			return linkTask{}, false
+		}
+	}
+	var channelKey string
+	if hasKey {
+		channelKey = key.String()
+	}
+
+	// Skip registration status check if it doesn't apply to the platform or followers count is < 10000
+	if !platform.RegistrationCheck || info.FollowersCount < 10000 {
+		info.RegistrationStatus = "not applicable ⚪"
+		analysis := database.NewInfluencerAnalysis(
+			userId,                  // UserID
+			info.ChannelName,        // ChannelName
+			info.OriginalLink,       // Link
+			info.Platform,           // Platform
+			info.FollowersCount,     // FollowersCount
+			info.FollowersText,      // FollowersText
+			info.RegistrationStatus, // RegistrationStatus
+		)
+		analysis.ChannelKey = channelKey
+		analysis.Attempts = info.Attempts
+		analysis.VideosCount = info.VideosCount
+		resultsList[idx] = analysisResult(analysis)
+		reportProgress(newLinkResult(idx, analysis))
+		if err := a.influencersRepository.SaveInfluencerAnalysis(analysis); err != nil {
+			log.Printf("Error saving analysis for %s: %v", info.OriginalLink, err)
+		}
+		return linkTask{}, false
+	}
+
+	return linkTask{index: idx, link: link, channelKey: channelKey, info: info}, true
+}
+
+// reuseAnalysis reports the stored analysis of the channel of the link when there is one.
+// Links without a channel key are looked up by link. Analyses of other users are copied for the user,
+// so the channel shows up in their history. A user keeps a single copy of a channel.
+func (a *InfluencerApp) reuseAnalysis(userId string, idx int, link string, key extractor.ChannelKey, hasKey bool, resultsList []filemanager.Result, reportProgress func(*LinkResult)) bool {
+	var resp *database.InfluencerAnalysis
+	var err error
+	if hasKey {
+		// The own analysis of the user comes first, so a channel reused again doesn't make another copy
+		resp, err = a.influencersRepository.GetUserInfluencerAnalysisByChannelKey(userId, key.String())
+		if resp == nil || err != nil {
+			resp, err = a.influencersRepository.GetInfluencerAnalysisByChannelKey(key.String())
+		}
+	} else {
+		resp, err = a.influencersRepository.GetInfluencerAnalysisByLink(link)
+	}
+	if err != nil {
+		log.Printf("Error fetching analysis for %s: %v", link, err)
+	}
+	if resp == nil || err != nil {
+		return false
+	}
+
+	log.Printf("Channel of %s already processed, getting from database.", link)
+	// The stored analysis may come from another link of the channel, the row keeps the link of the input
+	reused := *resp
+	reused.Link = link
+	// The result keeps the date of the original analysis
+	resultsList[idx] = analysisResult(&reused)
+	reportProgress(newLinkResult(idx, &reused))
+
+	if resp.UserID != userId {
+		// The copy keeps the dates of the original, reusing it doesn't make the data fresher.
+		// Copies of copies point at the original analysis.
+		reused.ID = ""
+		reused.UserID = userId
+		if reused.ReusedFrom == "" {
+			reused.ReusedFrom = resp.ID
+		}
+		if err := a.influencersRepository.SaveReusedAnalysis(&reused); err != nil {
+			log.Printf("Error saving the reused analysis of %s: %v", link, err)
+		}
+	}
+	return true
+}
+
+// checkRegistration checks the registration status of the extracted channel and stores its result
+func (a *InfluencerApp) checkRegistration(ctx context.Context, userId string, task linkTask, resultsList []filemanager.Result, reportProgress func(*LinkResult)) {
+	currentInfo := task.info
+
+	var isRegistered bool
+	attempts, err := a.registrationRetry.Do(ctx, func(ctx context.Context) error {
+		release, err := a.limiter.Acquire(ctx, RegistrationPlatform)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		isRegistered, err = ruregistration.CheckRegistrationStatus(ctx, a.runner, task.link)
+		return err
+	})
+	// The check was interrupted, the result must not be stored
+	if ctx.Err() != nil {
+		return
+	}
+	// Without a registration status the analysis is incomplete, it is not stored
+	if err != nil {
+		err = fmt.Errorf("registration check: %w", err)
+		resultsList[task.index] = failedResult(currentInfo, err)
+		reportProgress(newFailedLinkResult(task.index, currentInfo, err))
+		return
+	}
+
+	currentInfo.IsRegistered = isRegistered
+	if currentInfo.IsRegistered {
+		currentInfo.RegistrationStatus = "registered 🟢"
+	} else {
+		currentInfo.RegistrationStatus = "not registered 🔴"
+	}
+
+	analysis := database.NewInfluencerAnalysis(
+		userId,                         // UserID
+		currentInfo.ChannelName,        // ChannelName
+		currentInfo.OriginalLink,       // Link
+		currentInfo.Platform,           // Platform
+		currentInfo.FollowersCount,     // FollowersCount
+		currentInfo.FollowersText,      // FollowersText
+		currentInfo.RegistrationStatus, // RegistrationStatus
+	)
+	analysis.ChannelKey = task.channelKey
+	analysis.Attempts = currentInfo.Attempts
+	analysis.VideosCount = currentInfo.VideosCount
+	analysis.RegistrationAttempts = attempts
+	if err := a.influencersRepository.SaveInfluencerAnalysis(analysis); err != nil {
+		log.Printf("Error saving analysis for %s: %v", currentInfo.OriginalLink, err)
+	}
+	resultsList[task.index] = analysisResult(analysis)
+	reportProgress(newLinkResult(task.index, analysis))
+}
+
+// extract runs the extractor that can handle the link, it also returns the platform of the link
+func (a *InfluencerApp) extract(ctx context.Context, link string) (extractor.ChannelInfo, extractor.Platform, error) {
+	e, ok := a.registry.Lookup(link)
+	if !ok {
+		return extractor.ChannelInfo{OriginalLink: link, Platform: "Unknown"}, extractor.Platform{}, ErrNoExtractor
+	}
+	info, err := e.Extract(ctx, link)
+	info.Platform = e.Name()
+	if info.OriginalLink == "" {
+		info.OriginalLink = link
+	}
+	return info, e.Platform(), err
+}
+
+// analysisResult is the result of a successful analysis
+func analysisResult(analysis *database.InfluencerAnalysis) filemanager.Result {
+	followersCount := analysis.FollowersCount
+	return filemanager.Result{
+		ChannelName:        analysis.ChannelName,
+		FollowersCount:     &followersCount,
+		Link:               analysis.Link,
+		Platform:           analysis.Platform,
+		RegistrationStatus: analysis.RegistrationStatus,
+		AnalyzedAt:         analysis.CreatedAt,
+	}
+}
+
+// failedResult is the result of a link that could not be analyzed, its followers are kept when they were read
+func failedResult(info extractor.ChannelInfo, err error) filemanager.Result {
+	result := filemanager.Result{
+		ChannelName: info.ChannelName,
+		Link:        info.OriginalLink,
+		Platform:    info.Platform,
+		Error:       err.Error(),
+		ErrorCode:   errorCode(err),
+		AnalyzedAt:  time.Now(),
+	}
+	if info.FollowersText != "" {
+		followersCount := info.FollowersCount
+		result.FollowersCount = &followersCount
+	}
+	return result
+}
+
+func newFailedLinkResult(index int, info extractor.ChannelInfo, err error) *LinkResult {
+	return &LinkResult{
+		Index:          index,
+		ChannelName:    info.ChannelName,
+		FollowersCount: info.FollowersCount,
+		Link:           info.OriginalLink,
+		Platform:       info.Platform,
+		Error:          err.Error(),
+		ErrorCode:      errorCode(err),
+	}
+}
+
+// errorCode classifies the error of a failed link for the API consumers
+func errorCode(err error) string {
+	if errors.Is(err, ErrNoExtractor) {
+		return "unsupported_link"
+	}
+	if errors.Is(err, ErrLinkPanic) {
+		return "internal_error"
+	}
+	return extractor.ErrorCode(err)
+}
+
+func newLinkResult(index int, analysis *database.InfluencerAnalysis) *LinkResult {
+	return &LinkResult{
+		Index:              index,
+		ChannelName:        analysis.ChannelName,
+		FollowersCount:     analysis.FollowersCount,
+		Link:               analysis.Link,
+		Platform:           analysis.Platform,
+		RegistrationStatus: string(analysis.RegistrationStatus),
+	}
+}
+
+// SearchAnalyses returns a page of the analyses the user can see. Users only see their own analyses,
+// admins see those of every user and can filter them by owner.
+func (a *InfluencerApp) SearchAnalyses(userID string, role database.Role, query database.AnalysisQuery) (database.AnalysisPage, error) {
+	if userID == "" {
+		return database.AnalysisPage{}, ErrInvalidUserID
+	}
+	if !role.IsAdmin() {
+		if query.Filter.UserID != "" && query.Filter.UserID != userID {
+			return database.AnalysisPage{}, ErrForbiddenOwner
+		}
+		query.Filter.UserID = userID
+	}
+	return a.influencersRepository.SearchInfluencerAnalyses(query)
 }
 
-func (a *InfluencerApp) EstimateProcessingTime(inputFile string) (int, error) {
-	return a.fileManager.EstimateProcessingTime(inputFile)
+func (a *InfluencerApp) EstimateProcessingTime(inputFile string, opts RunOptions) (int, error) {
+	return a.fileManager.EstimateProcessingTime(inputFile, opts.Input)
 }
@@ -0,0 +1,503 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+	"github.com/solrac97gr/telegram-followers-checker/ratelimit"
+	"github.com/xuri/excelize/v2"
+)
+
+// memoryInfluencerRepository is an in memory InfluencerRepository, the analyses of a channel are found by channel key
+type memoryInfluencerRepository struct {
+	mutex    sync.Mutex
+	analyses []*database.InfluencerAnalysis
+	queries  []database.AnalysisQuery
+}
+
+var _ database.InfluencerRepository = (*memoryInfluencerRepository)(nil)
+
+func newMemoryInfluencerRepository(analyses ...*database.InfluencerAnalysis) *memoryInfluencerRepository {
+	mr := &memoryInfluencerRepository{}
+	for _, analysis := range analyses {
+		mr.SaveInfluencerAnalysis(analysis)
+	}
+	return mr
+}
+
+func (mr *memoryInfluencerRepository) SaveInfluencerAnalysis(influencer *database.InfluencerAnalysis) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	copied := *influencer
+	if copied.ID == "" {
+		copied.ID = fmt.Sprintf("analysis-%d", len(mr.analyses))
+	}
+	mr.analyses = append(mr.analyses, &copied)
+	return nil
+}
+
+func (mr *memoryInfluencerRepository) GetInfluencerAnalysisByLink(link string) (*database.InfluencerAnalysis, error) {
+	return nil, errors.New("not found")
+}
+
+func (mr *memoryInfluencerRepository) GetInfluencerAnalysisByChannelKey(channelKey string) (*database.InfluencerAnalysis, error) {
+	return mr.latest(func(analysis *database.InfluencerAnalysis) bool {
+		return analysis.ChannelKey == channelKey
+	})
+}
+
+func (mr *memoryInfluencerRepository) GetUserInfluencerAnalysisByChannelKey(userID string, channelKey string) (*database.InfluencerAnalysis, error) {
+	return mr.latest(func(analysis *database.InfluencerAnalysis) bool {
+		return analysis.UserID == userID && analysis.ChannelKey == channelKey
+	})
+}
+
+func (mr *memoryInfluencerRepository) SaveReusedAnalysis(analysis *database.InfluencerAnalysis) error {
+	mr.mutex.Lock()
+	for i, stored := range mr.analyses {
+		if stored.UserID == analysis.UserID && stored.ReusedFrom != "" && stored.ChannelKey == analysis.ChannelKey {
+			copied := *analysis
+			copied.ID = stored.ID
+			mr.analyses[i] = &copied
+			mr.mutex.Unlock()
+			return nil
+		}
+	}
+	mr.mutex.Unlock()
+	return mr.SaveInfluencerAnalysis(analysis)
+}
+
+// latest returns the newest matching analysis, the last saved one wins a tie like a copy keeping its date would
+func (mr *memoryInfluencerRepository) latest(match func(*database.InfluencerAnalysis) bool) (*database.InfluencerAnalysis, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	var found *database.InfluencerAnalysis
+	for _, analysis := range mr.analyses {
+		if match(analysis) && (found == nil || !analysis.CreatedAt.Before(found.CreatedAt)) {
+			found = analysis
+		}
+	}
+	if found == nil {
+		return nil, errors.New("not found")
+	}
+	copied := *found
+	return &copied, nil
+}
+
+// userAnalyses returns the stored analyses of the user
+func (mr *memoryInfluencerRepository) userAnalyses(userID string) []*database.InfluencerAnalysis {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	var analyses []*database.InfluencerAnalysis
+	for _, analysis := range mr.analyses {
+		if analysis.UserID == userID {
+			analyses = append(analyses, analysis)
+		}
+	}
+	return analyses
+}
+
+func (mr *memoryInfluencerRepository) DeleteExpiredAnalyses() error {
+	return nil
+}
+
+func (mr *memoryInfluencerRepository) SearchInfluencerAnalyses(query database.AnalysisQuery) (database.AnalysisPage, error) {
+	mr.queries = append(mr.queries, query)
+	return database.AnalysisPage{}, nil
+}
+
+func TestSearchAnalysesOwnerScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		role      database.Role
+		owner     string
+		wantOwner string
+		wantErr   error
+	}{
+		{"user without owner", "alice", database.UserRole, "", "alice", nil},
+		{"user with own owner", "alice", database.UserRole, "alice", "alice", nil},
+		{"user with other owner", "alice", database.UserRole, "bob", "", ErrForbiddenOwner},
+		{"user without role", "alice", "", "", "alice", nil},
+		{"admin without owner", "root", database.AdminRole, "", "", nil},
+		{"admin with owner", "root", database.AdminRole, "bob", "bob", nil},
+		{"super admin with owner", "root", database.SuperAdminRole, "bob", "bob", nil},
+		{"no user", "", database.AdminRole, "", "", ErrInvalidUserID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryInfluencerRepository{}
+			influencers := &InfluencerApp{influencersRepository: repo}
+
+			query := database.AnalysisQuery{Filter: database.AnalysisFilter{UserID: tt.owner}, Limit: 10}
+			_, err := influencers.SearchAnalyses(tt.userID, tt.role, query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchAnalyses error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.queries) != 0 {
+					t.Fatalf("a rejected search reached the repository: %+v", repo.queries)
+				}
+				return
+			}
+			if len(repo.queries) != 1 || repo.queries[0].Filter.UserID != tt.wantOwner {
+				t.Fatalf("queries = %+v, want one search of owner %q", repo.queries, tt.wantOwner)
+			}
+		})
+	}
+}
+
+func TestReuseAnalysisSavesCopyForUser(t *testing.T) {
+	key := extractor.ChannelKey{Platform: "telegram", Channel: "durov"}
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	expiration := createdAt.Add(24 * time.Hour)
+	stored := &database.InfluencerAnalysis{
+		ID:                 "analysis-bob",
+		UserID:             "bob",
+		ChannelName:        "Durov",
+		FollowersCount:     8871934,
+		Link:               "https://t.me/durov",
+		ChannelKey:         key.String(),
+		Platform:           "telegram",
+		RegistrationStatus: database.Registered,
+		CreatedAt:          createdAt,
+		ExpirationDate:     expiration,
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		wantCopy bool
+	}{
+		{"analysis of another user", "alice", true},
+		{"own analysis", "bob", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryInfluencerRepository(stored)
+			influencers := &InfluencerApp{influencersRepository: repo}
+			results := make([]filemanager.Result, 1)
+			var reported *LinkResult
+
+			link := "https://t.me/s/durov"
+			if !influencers.reuseAnalysis(tt.userID, 0, link, key, true, results, func(r *LinkResult) { reported = r }) {
+				t.Fatal("reuseAnalysis did not reuse the stored analysis")
+			}
+			if results[0].Link != link || !results[0].AnalyzedAt.Equal(createdAt) {
+				t.Errorf("result = %+v, want the input link and the date of the stored analysis", results[0])
+			}
+			if reported == nil || reported.FollowersCount != stored.FollowersCount {
+				t.Errorf("reported = %+v, want the stored followers", reported)
+			}
+
+			analyses := repo.userAnalyses(tt.userID)
+			if !tt.wantCopy {
+				if len(analyses) != 1 || analyses[0].ReusedFrom != "" {
+					t.Fatalf("analyses = %+v, want no copy of an own analysis", analyses)
+				}
+				return
+			}
+			if len(analyses) != 1 {
+				t.Fatalf("%s has %d analyses, want one copy", tt.userID, len(analyses))
+			}
+			copied := analyses[0]
+			if copied.ID == stored.ID || copied.UserID != tt.userID || copied.ReusedFrom != stored.ID {
+				t.Errorf("copy = %+v, want a new analysis of %s reused from %s", copied, tt.userID, stored.ID)
+			}
+			if copied.Link != link || copied.ChannelKey != stored.ChannelKey || copied.FollowersCount != stored.FollowersCount {
+				t.Errorf("copy = %+v, want the channel of the stored analysis with the input link", copied)
+			}
+			if !copied.CreatedAt.Equal(createdAt) {
+				t.Errorf("copy created at %v, want the date of the stored analysis %v", copied.CreatedAt, createdAt)
+			}
+			if !copied.ExpirationDate.Equal(expiration) {
+				t.Errorf("copy expires at %v, want the expiration of the stored analysis %v", copied.ExpirationDate, expiration)
+			}
+		})
+	}
+}
+
+func TestReuseAnalysisKeepsOneCopyPerUser(t *testing.T) {
+	key := extractor.ChannelKey{Platform: "telegram", Channel: "durov"}
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	original := &database.InfluencerAnalysis{
+		ID:                 "analysis-bob",
+		UserID:             "bob",
+		ChannelName:        "Durov",
+		FollowersCount:     8871934,
+		Link:               "https://t.me/durov",
+		ChannelKey:         key.String(),
+		Platform:           "telegram",
+		RegistrationStatus: database.Registered,
+		CreatedAt:          createdAt,
+		ExpirationDate:     createdAt.Add(24 * time.Hour),
+	}
+	repo := newMemoryInfluencerRepository(original)
+	influencers := &InfluencerApp{influencersRepository: repo}
+
+	// The users upload the channel in turn, carol finds the copy of alice as the latest analysis
+	uploads := []struct {
+		userID    string
+		wantCount int
+	}{
+		{userID: "alice", wantCount: 2},
+		{userID: "bob", wantCount: 2},
+		{userID: "alice", wantCount: 2},
+		{userID: "carol", wantCount: 3},
+		{userID: "alice", wantCount: 3},
+		{userID: "carol", wantCount: 3},
+		{userID: "bob", wantCount: 3},
+	}
+	for i, upload := range uploads {
+		results := make([]filemanager.Result, 1)
+		if !influencers.reuseAnalysis(upload.userID, 0, "https://t.me/durov", key, true, results, func(*LinkResult) {}) {
+			t.Fatalf("upload %d of %s did not reuse the stored analysis", i, upload.userID)
+		}
+		if !results[0].AnalyzedAt.Equal(createdAt) {
+			t.Errorf("upload %d of %s analyzed at %v, want the date of the original %v", i, upload.userID, results[0].AnalyzedAt, createdAt)
+		}
+		if count := len(repo.analyses); count != upload.wantCount {
+			t.Fatalf("after upload %d of %s the repository has %d analyses, want %d", i, upload.userID, count, upload.wantCount)
+		}
+	}
+
+	for _, userID := range []string{"alice", "carol"} {
+		copies := repo.userAnalyses(userID)
+		if len(copies) != 1 {
+			t.Fatalf("%s has %d analyses, want one copy", userID, len(copies))
+		}
+		if copies[0].ReusedFrom != original.ID || !copies[0].CreatedAt.Equal(createdAt) {
+			t.Errorf("copy of %s = %+v, want the date of the original and a reference to %s", userID, copies[0], original.ID)
+		}
+	}
+}
+
+// testExtractor handles the Telegram links, the channel is the first segment of their path
+type testExtractor struct{}
+
+var _ extractor.StatisticExtractor = (*testExtractor)(nil)
+
+func (te *testExtractor) CanHandle(link string) bool {
+	return te.Platform().Matches(link)
+}
+
+func (te *testExtractor) Platform() extractor.Platform {
+	return extractor.Platform{
+		Hosts:         []string{"t.me", "telegram.me"},
+		CanonicalHost: "t.me",
+		Channel: func(u *url.URL) string {
+			if segments := extractor.PathSegments(u); len(segments) > 0 {
+				return strings.ToLower(segments[0])
+			}
+			return ""
+		},
+	}
+}
+
+func (te *testExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ChannelInfo{}, extractor.ErrNotFound
+}
+
+func (te *testExtractor) Name() string {
+	return "telegram"
+}
+
+// newReusingInfluencerApp creates an InfluencerApp whose channels are all stored already, so no script is run
+func newReusingInfluencerApp(channels ...string) *InfluencerApp {
+	registry := extractor.NewRegistry(&testExtractor{})
+	repo := newMemoryInfluencerRepository()
+	for _, channel := range channels {
+		key := extractor.ChannelKey{Platform: "telegram", Channel: channel}
+		repo.SaveInfluencerAnalysis(&database.InfluencerAnalysis{
+			ID:                 "analysis-" + channel,
+			UserID:             "alice",
+			ChannelName:        channel,
+			FollowersCount:     1000,
+			Link:               "https://t.me/" + channel,
+			ChannelKey:         key.String(),
+			Platform:           "telegram",
+			RegistrationStatus: database.Registered,
+			CreatedAt:          time.Now(),
+			ExpirationDate:     time.Now().Add(time.Hour),
+		})
+	}
+	return &InfluencerApp{
+		influencersRepository: repo,
+		fileManager:           filemanager.NewFileManager(registry),
+		registry:              registry,
+		extractionWorkers:     2,
+		registrationWorkers:   1,
+	}
+}
+
+func TestRunDuplicateNotes(t *testing.T) {
+	dir := t.TempDir()
+	csvInput := filepath.Join(dir, "links.csv")
+	if err := os.WriteFile(csvInput, []byte("https://t.me/durov\nhttps://t.me/team\nhttps://telegram.me/Durov\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The links of the second sheet are found after an empty row
+	workbook := excelize.NewFile()
+	workbook.SetSheetName("Sheet1", "Channels")
+	workbook.SetSheetRow("Channels", "A1", &[]any{"Name", "Link"})
+	workbook.SetSheetRow("Channels", "A2", &[]any{"Durov", "https://t.me/durov"})
+	workbook.SetSheetRow("Channels", "A3", &[]any{"Team", "https://t.me/team"})
+	workbook.NewSheet("More")
+	workbook.SetSheetRow("More", "A1", &[]any{"Name", "Link"})
+	workbook.SetSheetRow("More", "A4", &[]any{"Durov again", "https://telegram.me/durov"})
+	xlsxInput := filepath.Join(dir, "links.xlsx")
+	if err := workbook.SaveAs(xlsxInput); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		opts  RunOptions
+		want  string
+	}{
+		{"xlsx output", csvInput, RunOptions{}, "duplicate of row 2"},
+		{"csv output", csvInput, RunOptions{Format: filemanager.FormatCSV}, "duplicate of row 2"},
+		{"json output", csvInput, RunOptions{Format: filemanager.FormatJSON}, "duplicate of item 1"},
+		{"jsonl output", csvInput, RunOptions{Format: filemanager.FormatJSONL}, "duplicate of item 1"},
+		{"enriched csv", csvInput, RunOptions{Enrich: true}, "duplicate of row 1"},
+		{
+			name:  "enriched workbook",
+			input: xlsxInput,
+			opts:  RunOptions{Enrich: true, Input: filemanager.ReadOptions{AllSheets: true, Column: "Link", HeaderRow: 1}},
+			want:  "duplicate of row 2 of sheet Channels",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			influencers := newReusingInfluencerApp("durov", "team")
+			var notes []string
+			onProgress := func(processed int, total int, result *LinkResult) {
+				if result != nil && result.Note != "" {
+					notes = append(notes, result.Note)
+				}
+			}
+
+			results, err := influencers.Run(context.Background(), "alice", tt.input, filepath.Join(t.TempDir(), "output.xlsx"), tt.opts, onProgress)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("got %d results, want 3", len(results))
+			}
+			if results[0].Note != "" || results[1].Note != "" {
+				t.Errorf("notes = %q, %q, want none on the first links", results[0].Note, results[1].Note)
+			}
+			if results[2].Note != tt.want || results[2].DuplicateOf != 1 {
+				t.Errorf("duplicate = %+v, want the note %q", results[2], tt.want)
+			}
+			if len(notes) != 1 || notes[0] != tt.want {
+				t.Errorf("reported notes = %q, want %q", notes, tt.want)
+			}
+		})
+	}
+}
+
+// panicExtractor panics on the links of the extract path, the channels of the other links
+// have enough followers for their registration to be checked
+type panicExtractor struct{}
+
+var _ extractor.StatisticExtractor = (*panicExtractor)(nil)
+
+func (pe *panicExtractor) CanHandle(link string) bool {
+	return pe.Platform().Matches(link)
+}
+
+func (pe *panicExtractor) Platform() extractor.Platform {
+	return extractor.Platform{
+		Hosts:             []string{"panic.test"},
+		CanonicalHost:     "panic.test",
+		RegistrationCheck: true,
+	}
+}
+
+func (pe *panicExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	if strings.Contains(link, "/extract") {
+		panic("extractor crashed")
+	}
+	return extractor.ChannelInfo{ChannelName: "Panic", FollowersCount: 20000, FollowersText: "20K"}, nil
+}
+
+func (pe *panicExtractor) Name() string {
+	return "panic"
+}
+
+// panicRunner panics on every script run
+type panicRunner struct{}
+
+func (panicRunner) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	panic("runner crashed")
+}
+
+func TestProcessLinksRecoversPanics(t *testing.T) {
+	influencers := newReusingInfluencerApp("durov")
+	influencers.registry = extractor.NewRegistry(&testExtractor{}, &panicExtractor{})
+	influencers.limiter = ratelimit.NewLimiter(nil)
+	influencers.runner = panicRunner{}
+	links := []string{"https://t.me/durov", "https://panic.test/extract", "https://panic.test/register"}
+
+	var mutex sync.Mutex
+	reported := make(map[int]int)
+	lastProcessed := 0
+	onProgress := func(processed int, total int, result *LinkResult) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		lastProcessed = max(lastProcessed, processed)
+		if result != nil {
+			reported[result.Index]++
+		}
+	}
+
+	results, err := influencers.processLinks(context.Background(), "alice", links, formatPosition("csv"), onProgress)
+	if err != nil {
+		t.Fatalf("processLinks: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		index         int
+		wantCode      string
+		wantFollowers bool
+	}{
+		{name: "stored", index: 0},
+		{name: "extraction panic", index: 1, wantCode: "internal_error"},
+		{name: "registration panic", index: 2, wantCode: "internal_error", wantFollowers: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results[tt.index]
+			if result.ErrorCode != tt.wantCode {
+				t.Errorf("error code = %q (%s), want %q", result.ErrorCode, result.Error, tt.wantCode)
+			}
+			if result.Link != links[tt.index] {
+				t.Errorf("link = %q, want %q", result.Link, links[tt.index])
+			}
+			if tt.wantFollowers && (result.FollowersCount == nil || *result.FollowersCount != 20000) {
+				t.Errorf("followers = %v, want the extracted 20000", result.FollowersCount)
+			}
+			if reported[tt.index] != 1 {
+				t.Errorf("reported %d times, want once", reported[tt.index])
+			}
+		})
+	}
+	if lastProcessed != len(links) {
+		t.Errorf("processed = %d, want %d", lastProcessed, len(links))
+	}
+}
@@ -0,0 +1,408 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrInvalidJob      = errors.New("invalid job")
+	ErrJobQueueFull    = errors.New("job queue is full")
+	ErrJobFinished     = errors.New("job already finished")
+	ErrJobNotCompleted = errors.New("job not completed")
+)
+
+const jobQueueSize = 1024
+
+// JobApp persists analysis jobs and drains them with a pool of workers
+type JobApp struct {
+	repository    database.JobRepository
+	influencerApp *InfluencerApp
+	artifacts     *ArtifactApp
+	events        *JobEventBroker
+	workers       int
+	queue         chan string
+
+	mutex     sync.Mutex
+	running   map[string]context.CancelFunc // Cancels the jobs being processed
+	cancelled map[string]struct{}           // Jobs cancelled before a worker picked them up
+}
+
+// NewJobApp creates a new JobApp instance
+func NewJobApp(repository database.JobRepository, influencerApp *InfluencerApp, artifacts *ArtifactApp, workers int) *JobApp {
+	if repository == nil {
+		log.Fatal("jobRepository cannot be nil")
+	}
+	if influencerApp == nil {
+		log.Fatal("influencerApp cannot be nil")
+	}
+	if artifacts == nil {
+		log.Fatal("artifactApp cannot be nil")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &JobApp{
+		repository:    repository,
+		influencerApp: influencerApp,
+		artifacts:     artifacts,
+		events:        NewJobEventBroker(),
+		workers:       workers,
+		queue:         make(chan string, jobQueueSize),
+		running:       make(map[string]context.CancelFunc),
+		cancelled:     make(map[string]struct{}),
+	}
+}
+
+// Start launches the workers and re-queues the jobs left unfinished by a previous run
+func (j *JobApp) Start() {
+	for i := 0; i < j.workers; i++ {
+		go j.worker(i)
+	}
+
+	jobs, err := j.repository.GetJobsByStatus(database.JobPending, database.JobRunning)
+	if err != nil {
+		log.Printf("Error fetching unfinished jobs: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		log.Printf("Re-queueing unfinished job %s", job.ID)
+		if err := j.enqueue(job.ID); err != nil {
+			log.Printf("Error re-queueing job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// Enqueue persists a new job for the given input file and schedules it, its options are stored with it.
+// The errors of the input file are returned right away.
+func (j *JobApp) Enqueue(userID string, inputFile string, outputFile string, opts RunOptions) (*database.Job, error) {
+	if userID == "" || inputFile == "" || outputFile == "" {
+		return nil, ErrInvalidJob
+	}
+
+	// Bad files are rejected before they are queued
+	if err := j.influencerApp.ValidateInput(inputFile, opts); err != nil {
+		return nil, err
+	}
+
+	job := database.NewJob(userID, inputFile, outputFile, opts.JobOptions())
+	if err := j.repository.SaveJob(job); err != nil {
+		return nil, err
+	}
+	if err := j.enqueue(job.ID); err != nil {
+		j.finish(job, database.JobFailed, err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJob returns the job only if it belongs to the given user
+func (j *JobApp) GetJob(userID string, jobID string) (*database.Job, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+	job, err := j.repository.GetJobByID(jobID)
+	if err != nil || job.UserID != userID {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// ExportResults returns the artifact of the results of the completed job of the given user in the format.
+// Other formats than the one of the upload, and the files that expired, are written again from the stored results.
+func (j *JobApp) ExportResults(userID string, jobID string, format string) (*database.Artifact, error) {
+	job, err := j.GetJob(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != database.JobCompleted {
+		return nil, ErrJobNotCompleted
+	}
+	jobFormat, err := filemanager.ParseFormat(job.Options.Format)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = jobFormat
+	}
+	if format, err = filemanager.ParseFormat(format); err != nil {
+		return nil, err
+	}
+	if artifact := j.artifacts.findByJob(job, format); artifact != nil {
+		return artifact, nil
+	}
+	// The input columns of an enriched file are not stored with the job
+	if job.Options.Enrich && format == jobFormat {
+		return nil, ErrArtifactExpired
+	}
+
+	// The displayed rows leave out the error codes and the dates of the analyses, the records keep them
+	if len(job.Records) == 0 && len(job.Results) > 1 {
+		return nil, fmt.Errorf("job %s has no stored results", job.ID)
+	}
+	results := make([]filemanager.Result, 0, len(job.Records))
+	for _, record := range job.Records {
+		results = append(results, filemanager.ResultFromRecord(record))
+	}
+	// The notes of the duplicates are numbered like the output of the job, they are numbered again for the export
+	setDuplicateNotes(results, formatPosition(format))
+	outputFile := j.artifacts.NewOutputPath(format)
+	if err := j.influencerApp.fileManager.SaveResults(results, format, outputFile); err != nil {
+		return nil, err
+	}
+	artifact, err := j.artifacts.Register(job, outputFile, format)
+	if err != nil {
+		if err := os.Remove(outputFile); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to delete export file %s: %v", outputFile, err)
+		}
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// ListJobs returns the jobs created by the given user, newest first
+func (j *JobApp) ListJobs(userID string, page int, limit int) (database.AllJobs, error) {
+	if userID == "" {
+		return database.AllJobs{}, ErrInvalidUserID
+	}
+	return j.repository.GetJobsByUserID(userID, page, limit)
+}
+
+// Cancel stops the job of the given user. A running job has its scripts killed,
+// a pending job is skipped once a worker picks it up.
+func (j *JobApp) Cancel(userID string, jobID string) error {
+	job, err := j.GetJob(userID, jobID)
+	if err != nil {
+		return err
+	}
+	if job.IsFinished() {
+		return ErrJobFinished
+	}
+
+	j.mutex.Lock()
+	cancel, running := j.running[job.ID]
+	if !running {
+		j.cancelled[job.ID] = struct{}{}
+	}
+	j.mutex.Unlock()
+
+	if running {
+		log.Printf("Cancelling running job %s", job.ID)
+		cancel()
+		return nil
+	}
+	log.Printf("Cancelling pending job %s", job.ID)
+	j.finish(job, database.JobCancelled, context.Canceled)
+	return nil
+}
+
+// SubscribeJobEvents returns the events of the job published so far and a channel with the upcoming ones.
+// Jobs that finished before the events were kept in memory are replayed from their stored results.
+func (j *JobApp) SubscribeJobEvents(job *database.Job) ([]JobEvent, <-chan JobEvent, func()) {
+	if job.IsFinished() && !j.events.Has(job.ID) {
+		closed := make(chan JobEvent)
+		close(closed)
+		return replayJobEvents(job), closed, func() {}
+	}
+	return j.events.Subscribe(job.ID)
+}
+
+func (j *JobApp) enqueue(jobID string) error {
+	select {
+	case j.queue <- jobID:
+		return nil
+	default:
+		return ErrJobQueueFull
+	}
+}
+
+func (j *JobApp) worker(id int) {
+	for jobID := range j.queue {
+		log.Printf("Worker %d picked up job %s", id, jobID)
+		j.process(jobID)
+	}
+}
+
+func (j *JobApp) process(jobID string) {
+	job, err := j.repository.GetJobByID(jobID)
+	if err != nil {
+		log.Printf("Error fetching job %s: %v", jobID, err)
+		return
+	}
+
+	defer func() {
+		if err := os.Remove(job.InputFile); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to delete input file %s: %v", job.InputFile, err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Register the job as running unless it was cancelled while queued
+	j.mutex.Lock()
+	_, cancelled := j.cancelled[job.ID]
+	delete(j.cancelled, job.ID)
+	if !cancelled {
+		j.running[job.ID] = cancel
+	}
+	j.mutex.Unlock()
+	if cancelled || job.IsFinished() {
+		log.Printf("Skipping job %s with status %s", job.ID, job.Status)
+		return
+	}
+	defer func() {
+		j.mutex.Lock()
+		delete(j.running, job.ID)
+		j.mutex.Unlock()
+	}()
+
+	if _, err := os.Stat(job.InputFile); err != nil {
+		j.finish(job, database.JobFailed, fmt.Errorf("input file is not available: %w", err))
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			j.finish(job, database.JobFailed, fmt.Errorf("job panicked: %v", r))
+		}
+	}()
+
+	job.Status = database.JobRunning
+	job.StartedAt = time.Now()
+	if err := j.repository.UpdateJob(job); err != nil {
+		log.Printf("Error updating job %s: %v", job.ID, err)
+	}
+
+	var failed int32
+	var progressMutex sync.Mutex
+	results, err := j.influencerApp.Run(ctx, job.UserID, job.InputFile, job.OutputFile, RunOptionsFromJob(job.Options), func(processed int, total int, result *LinkResult) {
+		// Links finish concurrently, keep the highest count seen
+		progressMutex.Lock()
+		if processed > job.ProcessedLinks {
+			job.ProcessedLinks = processed
+		}
+		job.TotalLinks = total
+		// The update is written under the lock, so a lower count never overwrites a higher one
+		if err := j.repository.UpdateJobProgress(job.ID, job.ProcessedLinks, total); err != nil {
+			log.Printf("Error updating progress for job %s: %v", job.ID, err)
+		}
+		progressMutex.Unlock()
+		if result != nil {
+			if result.Error != "" {
+				atomic.AddInt32(&failed, 1)
+			}
+			j.events.Publish(job.ID, LinkEventType, result)
+		}
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			j.finish(job, database.JobCancelled, err)
+		} else {
+			j.finish(job, database.JobFailed, err)
+		}
+		return
+	}
+
+	job.Status = database.JobCompleted
+	job.Results = resultRows(results)
+	job.Records = make([]database.JobResult, 0, len(results))
+	for _, result := range results {
+		job.Records = append(job.Records, result.Record())
+	}
+	job.TotalLinks = len(results)
+	job.ProcessedLinks = job.TotalLinks
+	job.FinishedAt = time.Now()
+	format, _ := filemanager.ParseFormat(job.Options.Format)
+	if artifact, err := j.artifacts.Register(job, job.OutputFile, format); err != nil {
+		// The results are kept with the job, they can still be exported
+		log.Printf("Error registering the output file of job %s: %v", job.ID, err)
+	} else {
+		job.ArtifactID = artifact.ID
+	}
+	if err := j.repository.UpdateJob(job); err != nil {
+		log.Printf("Error updating job %s: %v", job.ID, err)
+	}
+	j.events.Publish(job.ID, SummaryEventType, JobSummary{
+		Status:     string(job.Status),
+		Total:      job.TotalLinks,
+		Processed:  job.ProcessedLinks,
+		Failed:     int(atomic.LoadInt32(&failed)),
+		ArtifactID: job.ArtifactID,
+	})
+	j.events.Close(job.ID)
+	log.Printf("Job %s completed, results saved to %s", job.ID, job.OutputFile)
+}
+
+// finish stores the terminal status of a job that did not complete and ends its events stream
+func (j *JobApp) finish(job *database.Job, status database.JobStatus, cause error) {
+	log.Printf("Job %s %s: %v", job.ID, status, cause)
+	job.Status = status
+	job.Error = cause.Error()
+	job.FinishedAt = time.Now()
+	if err := j.repository.UpdateJob(job); err != nil {
+		log.Printf("Error updating job %s: %v", job.ID, err)
+	}
+	j.events.Publish(job.ID, SummaryEventType, JobSummary{
+		Status:    string(job.Status),
+		Total:     job.TotalLinks,
+		Processed: job.ProcessedLinks,
+		Error:     job.Error,
+	})
+	j.events.Close(job.ID)
+}
+
+// replayJobEvents rebuilds the events of a finished job from its stored results
+func replayJobEvents(job *database.Job) []JobEvent {
+	events := make([]JobEvent, 0, len(job.Results)+1)
+	for i, row := range job.Results {
+		if i == 0 || len(row) < 5 {
+			continue // Skip the header row
+		}
+		followersCount, _ := strconv.ParseInt(row[1], 10, 64)
+		result := &LinkResult{
+			Index:          i - 1,
+			ChannelName:    row[0],
+			FollowersCount: followersCount,
+			Link:           row[2],
+			Platform:       row[3],
+		}
+		if len(row) > 5 && row[5] != "" {
+			result.Error = row[5]
+		} else {
+			result.RegistrationStatus = string(database.ParseStatus(row[4]))
+		}
+		if len(row) > 6 {
+			result.Note = row[6]
+		}
+		events = append(events, JobEvent{
+			ID:   len(events) + 1,
+			Type: LinkEventType,
+			Data: result,
+		})
+	}
+	events = append(events, JobEvent{
+		ID:   len(events) + 1,
+		Type: SummaryEventType,
+		Data: JobSummary{
+			Status:     string(job.Status),
+			Total:      job.TotalLinks,
+			Processed:  job.ProcessedLinks,
+			ArtifactID: job.ArtifactID,
+			Error:      job.Error,
+		},
+	})
+	return events
+}
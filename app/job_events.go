@@ -0,0 +1,150 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	LinkEventType    = "link"
+	SummaryEventType = "summary"
+
+	// jobEventsRetention is how long the events of a finished job are kept for late subscribers
+	jobEventsRetention = 10 * time.Minute
+)
+
+// LinkResult is the outcome of processing a single link
+type LinkResult struct {
+	Index              int    `json:"index"`
+	ChannelName        string `json:"channel_name"`
+	FollowersCount     int64  `json:"followers_count"`
+	Link               string `json:"link"`
+	Platform           string `json:"platform"`
+	RegistrationStatus string `json:"registration_status"`
+	Error              string `json:"error,omitempty"`
+	ErrorCode          string `json:"error_code,omitempty"`
+	Note               string `json:"note,omitempty"` // "duplicate of row N" or "duplicate of item N" for the repeated links of a channel
+}
+
+// JobSummary is sent once a job reaches a terminal status
+type JobSummary struct {
+	Status     string `json:"status"`
+	Total      int    `json:"total"`
+	Processed  int    `json:"processed"`
+	Failed     int    `json:"failed"`
+	ArtifactID string `json:"artifact_id,omitempty"` // Artifact to download the output file with
+	Error      string `json:"error,omitempty"`
+}
+
+// JobEvent is a single progress notification for a job
+type JobEvent struct {
+	ID   int         `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type jobStream struct {
+	events      []JobEvent
+	subscribers map[chan JobEvent]struct{}
+	closed      bool
+}
+
+// JobEventBroker fans out job events to the connected subscribers.
+// Events are kept in memory so that subscribers joining late receive the full history.
+type JobEventBroker struct {
+	mutex   sync.Mutex
+	streams map[string]*jobStream
+}
+
+// NewJobEventBroker creates a new JobEventBroker instance
+func NewJobEventBroker() *JobEventBroker {
+	return &JobEventBroker{
+		streams: make(map[string]*jobStream),
+	}
+}
+
+// Publish records the event and sends it to every subscriber of the job
+func (b *JobEventBroker) Publish(jobID string, eventType string, data interface{}) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	stream := b.stream(jobID)
+	if stream.closed {
+		return
+	}
+	event := JobEvent{ID: len(stream.events) + 1, Type: eventType, Data: data}
+	stream.events = append(stream.events, event)
+	for subscriber := range stream.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// Slow subscriber, drop it instead of blocking the job
+			delete(stream.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Close ends the stream of the job, subscribers channels are closed
+func (b *JobEventBroker) Close(jobID string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	stream := b.stream(jobID)
+	if stream.closed {
+		return
+	}
+	stream.closed = true
+	for subscriber := range stream.subscribers {
+		delete(stream.subscribers, subscriber)
+		close(subscriber)
+	}
+
+	time.AfterFunc(jobEventsRetention, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.streams, jobID)
+	})
+}
+
+// Subscribe returns the events published so far and a channel with the upcoming ones.
+// The channel is closed when the job stream ends; unsubscribe must be called when done.
+func (b *JobEventBroker) Subscribe(jobID string) (history []JobEvent, events <-chan JobEvent, unsubscribe func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	stream := b.stream(jobID)
+	history = append([]JobEvent(nil), stream.events...)
+	subscriber := make(chan JobEvent, 64)
+	if stream.closed {
+		close(subscriber)
+		return history, subscriber, func() {}
+	}
+	stream.subscribers[subscriber] = struct{}{}
+
+	return history, subscriber, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		if _, ok := stream.subscribers[subscriber]; ok {
+			delete(stream.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Has reports whether events are being kept for the job
+func (b *JobEventBroker) Has(jobID string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	_, ok := b.streams[jobID]
+	return ok
+}
+
+func (b *JobEventBroker) stream(jobID string) *jobStream {
+	stream, ok := b.streams[jobID]
+	if !ok {
+		stream = &jobStream{subscribers: make(map[chan JobEvent]struct{})}
+		b.streams[jobID] = stream
+	}
+	return stream
+}
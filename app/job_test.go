@@ -0,0 +1,206 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+)
+
+// memoryJobRepository keeps the jobs in memory
+type memoryJobRepository struct {
+	mutex    sync.Mutex
+	jobs     map[string]*database.Job
+	progress []int // Processed counts of the progress updates, in the order they were written
+}
+
+var _ database.JobRepository = (*memoryJobRepository)(nil)
+
+func newMemoryJobRepository(jobs ...*database.Job) *memoryJobRepository {
+	mr := &memoryJobRepository{jobs: make(map[string]*database.Job)}
+	for _, job := range jobs {
+		mr.jobs[job.ID] = job
+	}
+	return mr
+}
+
+func (mr *memoryJobRepository) SaveJob(job *database.Job) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	stored := *job
+	mr.jobs[job.ID] = &stored
+	return nil
+}
+
+func (mr *memoryJobRepository) UpdateJob(job *database.Job) error {
+	return mr.SaveJob(job)
+}
+
+func (mr *memoryJobRepository) UpdateJobProgress(jobID string, processed int, total int) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	job, ok := mr.jobs[jobID]
+	if !ok {
+		return errors.New("job not found")
+	}
+	job.ProcessedLinks = processed
+	job.TotalLinks = total
+	mr.progress = append(mr.progress, processed)
+	return nil
+}
+
+func (mr *memoryJobRepository) GetJobByID(jobID string) (*database.Job, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+	job, ok := mr.jobs[jobID]
+	if !ok {
+		return nil, errors.New("job not found")
+	}
+	stored := *job
+	return &stored, nil
+}
+
+func (mr *memoryJobRepository) GetJobsByUserID(userID string, page int, limit int) (database.AllJobs, error) {
+	return database.AllJobs{}, nil
+}
+
+func (mr *memoryJobRepository) GetJobsByStatus(statuses ...database.JobStatus) ([]*database.Job, error) {
+	return nil, nil
+}
+
+func TestExportResultsKeepsTypedFields(t *testing.T) {
+	artifacts, _, _ := newTestArtifactApp(t)
+	followers := int64(8871934)
+	analyzedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	results := []filemanager.Result{
+		{ChannelName: "Durov", FollowersCount: &followers, Link: "https://t.me/durov", Platform: "telegram", RegistrationStatus: database.Registered, AnalyzedAt: analyzedAt},
+		{Link: "https://t.me/missing", Platform: "telegram", Error: "Channel not found", ErrorCode: "not_found"},
+		// The job wrote an xlsx file, its rows are numbered
+		{ChannelName: "Durov", FollowersCount: &followers, Link: "https://telegram.me/durov", Platform: "telegram", RegistrationStatus: database.Registered, Note: "duplicate of row 2", DuplicateOf: 1, AnalyzedAt: analyzedAt},
+	}
+	job := &database.Job{ID: "job-alice", UserID: "alice", Status: database.JobCompleted, Options: database.JobOptions{Format: filemanager.FormatXLSX}}
+	job.Results = resultRows(results)
+	for _, result := range results {
+		job.Records = append(job.Records, result.Record())
+	}
+
+	// Writing the results doesn't need the extractors
+	jobs := NewJobApp(newMemoryJobRepository(job), &InfluencerApp{fileManager: &filemanager.FileManagerImpl{}}, artifacts, 1)
+
+	artifact, err := jobs.ExportResults("alice", job.ID, filemanager.FormatJSON)
+	if err != nil {
+		t.Fatalf("ExportResults: %v", err)
+	}
+	file, err := artifacts.Open(artifact)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported []map[string]any
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("export is not a JSON array: %v\n%s", err, data)
+	}
+	if len(exported) != len(results) {
+		t.Fatalf("exported %d records, want %d", len(exported), len(results))
+	}
+	if exported[0]["analyzed_at"] != "2025-03-04T05:06:07Z" || exported[0]["followers_count"] != float64(followers) {
+		t.Errorf("first record = %v, want the followers and the date of the analysis", exported[0])
+	}
+	if exported[1]["error_code"] != "not_found" || exported[1]["followers_count"] != nil {
+		t.Errorf("second record = %v, want the error code and no followers", exported[1])
+	}
+	if exported[2]["note"] != "duplicate of item 1" {
+		t.Errorf("third record = %v, want the duplicate note numbered by item", exported[2])
+	}
+}
+
+func TestExportResultsWithoutRecords(t *testing.T) {
+	artifacts, _, _ := newTestArtifactApp(t)
+	// Jobs completed before the records were stored only have their displayed rows
+	job := &database.Job{
+		ID:      "job-alice",
+		UserID:  "alice",
+		Status:  database.JobCompleted,
+		Results: [][]string{filemanager.ResultHeader, {"Durov", "8871934", "https://t.me/durov", "telegram", "Registered", "", ""}},
+	}
+	// Writing the results doesn't need the extractors
+	jobs := NewJobApp(newMemoryJobRepository(job), &InfluencerApp{fileManager: &filemanager.FileManagerImpl{}}, artifacts, 1)
+
+	if _, err := jobs.ExportResults("alice", job.ID, filemanager.FormatCSV); err == nil {
+		t.Fatal("ExportResults rebuilt the results from their displayed rows")
+	}
+	if _, err := jobs.ExportResults("bob", job.ID, filemanager.FormatCSV); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("ExportResults of another user = %v, want ErrJobNotFound", err)
+	}
+}
+
+// slowProgressRepository delays the progress updates of odd counts, so concurrent updates are
+// written out of order unless they are serialized
+type slowProgressRepository struct {
+	*memoryJobRepository
+}
+
+func (sr slowProgressRepository) UpdateJobProgress(jobID string, processed int, total int) error {
+	if processed%2 == 1 {
+		time.Sleep(time.Millisecond)
+	}
+	return sr.memoryJobRepository.UpdateJobProgress(jobID, processed, total)
+}
+
+func TestProcessProgressIsMonotonic(t *testing.T) {
+	channels := make([]string, 50)
+	var input strings.Builder
+	for i := range channels {
+		channels[i] = fmt.Sprintf("channel%d", i)
+		input.WriteString("https://t.me/" + channels[i] + "\n")
+	}
+	influencers := newReusingInfluencerApp(channels...)
+	influencers.extractionWorkers = 8
+	artifacts, _, dir := newTestArtifactApp(t)
+	inputFile := filepath.Join(dir, "links.csv")
+	if err := os.WriteFile(inputFile, []byte(input.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	job := &database.Job{
+		ID:         "job-progress",
+		UserID:     "alice",
+		Status:     database.JobPending,
+		InputFile:  inputFile,
+		OutputFile: artifacts.NewOutputPath("csv"),
+		Options:    database.JobOptions{Format: "csv"},
+	}
+	repository := newMemoryJobRepository(job)
+	jobs := NewJobApp(slowProgressRepository{repository}, influencers, artifacts, 1)
+
+	jobs.process(job.ID)
+
+	stored, err := repository.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != database.JobCompleted {
+		t.Fatalf("status = %s (%s), want completed", stored.Status, stored.Error)
+	}
+	for i := 1; i < len(repository.progress); i++ {
+		if repository.progress[i] < repository.progress[i-1] {
+			t.Fatalf("progress went from %d to %d: %v", repository.progress[i-1], repository.progress[i], repository.progress)
+		}
+	}
+	if last := repository.progress[len(repository.progress)-1]; last != len(channels) {
+		t.Errorf("last progress = %d, want %d", last, len(channels))
+	}
+}
@@ -2,19 +2,17 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
+	"os/signal"
 	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/app"
 	"github.com/solrac97gr/telegram-followers-checker/config"
 	"github.com/solrac97gr/telegram-followers-checker/database"
-	instagram "github.com/solrac97gr/telegram-followers-checker/extractors/instagram"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/rutube"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/telegram"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/tiktok"
-	vk "github.com/solrac97gr/telegram-followers-checker/extractors/vk"
 	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+	"github.com/solrac97gr/telegram-followers-checker/wiring"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -25,14 +23,38 @@ func main() {
 		log.Fatalf("Error creating config: %v", err)
 	}
 
+	enrich := flag.Bool("enrich", false, "keep the rows and columns of the input file and append the results next to each link")
+	sheets := flag.String("sheets", "", `sheets to read: "all" or comma separated names, the first sheet by default`)
+	column := flag.String("column", "", "header name or letter of the column of the links, every column by default")
+	headerRow := flag.Int("header-row", 0, "1 based row of the headers, the rows above it are skipped; 0 when there is none")
+	format := flag.String("format", filemanager.FormatXLSX, "format of the output file: xlsx, csv, json or jsonl; the enrich mode writes xlsx only")
+	flag.Parse()
+
 	startAt := time.Now()
 	// Check if input argument is provided
-	if len(os.Args) < 2 {
-		log.Fatal("Please provide the path to the Excel file as an argument")
+	if flag.NArg() < 1 {
+		log.Fatal("Please provide the path to the input file (.xlsx, .xls, .ods, .csv, .tsv, .txt, .json or .jsonl) as an argument")
 	}
 
-	inputFile := os.Args[1]
-	outputFile := "channels_followers.xlsx"
+	inputFile := flag.Arg(0)
+	if *headerRow < 0 {
+		log.Fatal("The header row can't be negative")
+	}
+	opts := app.RunOptions{
+		Enrich: *enrich,
+		Input: filemanager.ReadOptions{
+			Column:    *column,
+			HeaderRow: *headerRow,
+		},
+	}
+	opts.Input.Sheets, opts.Input.AllSheets = filemanager.ParseSheets(*sheets)
+	if opts.Format, err = filemanager.ParseFormat(*format); err != nil {
+		log.Fatal(err)
+	}
+	if opts.Enrich && opts.Format != filemanager.FormatXLSX {
+		log.Fatal("The enrich mode writes xlsx files only")
+	}
+	outputFile := "channels_followers." + opts.Format
 
 	// Initialize MongoDB client
 	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(os.Getenv("MONGO_URI")))
@@ -46,17 +68,16 @@ func main() {
 		log.Fatalf("Error creating MongoDB repository: %v", err)
 	}
 
-	// Initialize components
-	fm := filemanager.NewFileManager()
-	telegramExtractor := telegram.NewTelegramExtractor()
-	rutubeExtractor := rutube.NewRutubeExtractor()
-	vkExtractor := vk.NewVKExtractor()
-	instagramExtractor := instagram.NewInstagramExtractor()
-	tiktokExtractor := tiktok.NewTikTokExtractor()
-
 	// Initialize and run app
-	application := app.NewInfluencerApp(repo, fm, telegramExtractor, rutubeExtractor, vkExtractor, instagramExtractor, tiktokExtractor)
-	application.Run("system", inputFile, outputFile)
+	application, closeRunner := wiring.NewInfluencerApp(config, repo)
+	defer closeRunner()
+	// Ctrl+C kills the running scripts instead of leaving browsers behind
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if _, err := application.Run(ctx, "system", inputFile, outputFile, opts, nil); err != nil {
+		log.Fatalf("Error processing links: %v", err)
+	}
 
 	log.Printf("Execution time: %v", time.Since(startAt))
 }
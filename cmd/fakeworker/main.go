@@ -0,0 +1,62 @@
+// Command fakeworker speaks the protocol of scripts/worker.js without launching any browser.
+// It answers every script with a canned result, which makes the worker pool usable in tests
+// and local runs without node or Chromium:
+//
+//	BROWSER_WORKER_COMMAND="go run ./cmd/fakeworker -delay 2s"
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+)
+
+type request struct {
+	ID     int      `json:"id"`
+	Type   string   `json:"type"`
+	Script string   `json:"script"`
+	Args   []string `json:"args"`
+}
+
+func main() {
+	result := flag.String("result", `{"channelName":"Fake Channel","followersCount":"12 345 subscribers"}`, "JSON result of the scraping scripts")
+	registered := flag.Bool("registered", false, "result of the registration checks")
+	delay := flag.Duration("delay", 0, "time spent on every script")
+	crashAfter := flag.Int("crash-after", 0, "exit with an error after this many scripts, 0 never crashes")
+	flag.Parse()
+
+	if !json.Valid([]byte(*result)) {
+		log.Fatalf("Invalid result %q", *result)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	handled := 0
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			log.Printf("Invalid request: %q", scanner.Text())
+			continue
+		}
+
+		if req.Type == "ping" {
+			_ = encoder.Encode(map[string]interface{}{"id": req.ID, "ok": true})
+			continue
+		}
+
+		handled++
+		if *crashAfter > 0 && handled > *crashAfter {
+			log.Fatalf("Crashing on request %d as requested", req.ID)
+		}
+		time.Sleep(*delay)
+
+		var response interface{} = json.RawMessage(*result)
+		if req.Script == "ru-registration" {
+			response = map[string]bool{"isRegistered": *registered}
+		}
+		_ = encoder.Encode(map[string]interface{}{"id": req.ID, "result": response})
+	}
+}
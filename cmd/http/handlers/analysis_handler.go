@@ -1,37 +1,132 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/database"
 )
 
+// AnalysesHandler searches the analyses of the user, admins search those of every user.
+// The next page is requested with the next_cursor of the previous one.
 func (h *Handlers) AnalysesHandler(c *fiber.Ctx) error {
-	page := c.Query("page", "1")
-	pageNum, err := strconv.Atoi(page)
-	if err != nil || pageNum < 1 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid page number",
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
 		})
 	}
-	limit := c.Query("limit", "10")
-	limitNum, err := strconv.Atoi(limit)
-	if err != nil || limitNum < 1 {
+	role, _ := c.Locals("role").(database.Role)
+
+	query, err := parseAnalysisQuery(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid limit number",
+			"error": err.Error(),
 		})
 	}
-	// Ensure the limit does not exceed a reasonable maximum
-	if limitNum > 100 {
-		limitNum = 100
-	}
 
-	analyses, err := h.InfluencerApp.GetAllInfluencerAnalysis(pageNum, limitNum)
+	page, err := h.InfluencerApp.SearchAnalyses(userID, role, query)
 	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrForbiddenOwner):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "You can only search your own analyses",
+			})
+		case errors.Is(err, database.ErrInvalidQuery), errors.Is(err, database.ErrInvalidCursor):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		log.Printf("Failed to search analyses: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to retrieve analyses",
 		})
 	}
 
-	return c.JSON(analyses)
+	return c.JSON(page)
+}
+
+// parseAnalysisQuery reads the filters, the sort and the page of the query parameters
+func parseAnalysisQuery(c *fiber.Ctx) (database.AnalysisQuery, error) {
+	query := database.AnalysisQuery{
+		Filter: database.AnalysisFilter{
+			UserID:      c.Query("owner"),
+			Platform:    strings.ToLower(strings.TrimSpace(c.Query("platform"))),
+			ChannelName: strings.TrimSpace(c.Query("channel")),
+		},
+		SortBy: c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 {
+		return query, errors.New("Invalid limit number")
+	}
+	// Ensure the limit does not exceed a reasonable maximum
+	query.Limit = min(limit, 100)
+
+	switch c.Query("order") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, errors.New("Invalid order. Use asc or desc")
+	}
+
+	switch status := database.Status(c.Query("status")); status {
+	case "", database.Registered, database.NotRegistered, database.NotApply:
+		query.Filter.RegistrationStatus = status
+	default:
+		return query, fmt.Errorf("Invalid status. Use %s, %s or %s", database.Registered, database.NotRegistered, database.NotApply)
+	}
+
+	if query.Filter.MinFollowers, err = parseFollowers(c.Query("min_followers")); err != nil {
+		return query, errors.New("Invalid min_followers")
+	}
+	if query.Filter.MaxFollowers, err = parseFollowers(c.Query("max_followers")); err != nil {
+		return query, errors.New("Invalid max_followers")
+	}
+
+	if query.Filter.CreatedFrom, err = parseQueryDate(c.Query("created_from"), false); err != nil {
+		return query, errors.New("Invalid created_from. Use a date (2006-01-02) or an RFC 3339 time")
+	}
+	if query.Filter.CreatedTo, err = parseQueryDate(c.Query("created_to"), true); err != nil {
+		return query, errors.New("Invalid created_to. Use a date (2006-01-02) or an RFC 3339 time")
+	}
+	return query, nil
+}
+
+func parseFollowers(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	followers, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || followers < 0 {
+		return nil, errors.New("invalid followers count")
+	}
+	return &followers, nil
+}
+
+// parseQueryDate reads an RFC 3339 time or a date. The end of a range includes its whole date.
+func parseQueryDate(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
 }
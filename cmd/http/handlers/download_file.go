@@ -1,8 +1,110 @@
 package handlers
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"errors"
+	"log"
 
+	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/storage"
+)
+
+// DownloadHandler sends the file of an artifact of the user, files are only reachable by artifact ID.
+// Storages with presigned URLs redirect to the storage, the others stream the file.
 func (h *Handlers) DownloadHandler(c *fiber.Ctx) error {
-	outputFile := c.Query("filename")
-	return c.Download(outputFile)
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	artifact, err := h.ArtifactApp.Get(userID, c.Params("id"))
+	if err != nil {
+		return artifactError(c, err)
+	}
+	downloadURL, err := h.ArtifactApp.DownloadURL(artifact)
+	if err == nil {
+		return c.Redirect(downloadURL, fiber.StatusFound)
+	}
+	if !errors.Is(err, storage.ErrPresignUnsupported) {
+		return artifactError(c, err)
+	}
+
+	file, err := h.ArtifactApp.Open(artifact)
+	if err != nil {
+		return artifactError(c, err)
+	}
+	c.Attachment(artifact.FileName)
+	// The file is closed once it is sent
+	return c.SendStream(file)
+}
+
+// ExportJobHandler returns the artifact of the results of a completed job in the format query parameter
+// (xlsx, csv, json or jsonl), the format of the upload when it is empty.
+func (h *Handlers) ExportJobHandler(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	jobID := c.Params("id")
+	artifact, err := h.JobApp.ExportResults(userID, jobID, c.Query("format"))
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrJobNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Job not found",
+			})
+		case errors.Is(err, app.ErrJobNotCompleted):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Job not completed",
+			})
+		case inputErrorStatus(err) != 0:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Unsupported format. Use xlsx, csv, json or jsonl",
+			})
+		case errors.Is(err, app.ErrArtifactExpired):
+			return artifactError(c, err)
+		}
+		log.Printf("Failed to export the results of job %s: %v", jobID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to export results",
+		})
+	}
+	response := fiber.Map{
+		"id":         artifact.ID,
+		"job_id":     artifact.JobID,
+		"file_name":  artifact.FileName,
+		"format":     artifact.Format,
+		"expires_at": artifact.ExpiresAt,
+		"created_at": artifact.CreatedAt,
+	}
+	// Browsers download presigned files straight from the storage
+	if downloadURL, err := h.ArtifactApp.DownloadURL(artifact); err == nil {
+		response["download_url"] = downloadURL
+	} else if !errors.Is(err, storage.ErrPresignUnsupported) {
+		log.Printf("Failed to presign artifact %s: %v", artifact.ID, err)
+	}
+	return c.JSON(response)
+}
+
+// artifactError answers with the status of an error of the artifacts, missing and foreign artifacts look the same
+func artifactError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, app.ErrArtifactNotFound), errors.Is(err, app.ErrInvalidUserID):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "File not found",
+		})
+	case errors.Is(err, app.ErrArtifactExpired):
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{
+			"error": "File expired",
+		})
+	}
+	log.Printf("Failed to open artifact: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to download file",
+	})
 }
@@ -10,6 +10,13 @@ import (
 )
 
 func (h *Handlers) EstimateTimeHandler(c *fiber.Ctx) error {
+	options, err := parseRunOptions(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	var inputFile string
 	var needsCleanup bool
 
@@ -34,16 +41,7 @@ func (h *Handlers) EstimateTimeHandler(c *fiber.Ctx) error {
 			})
 		}
 
-		// Check file extension
-		filename := strings.ToLower(file.Filename)
-		if !strings.HasSuffix(filename, ".xlsx") &&
-			!strings.HasSuffix(filename, ".xls") &&
-			!strings.HasSuffix(filename, ".csv") {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Unsupported file type. Please upload .xlsx, .xls, or .csv files",
-			})
-		}
-
+		// The format is detected by the file manager, unsupported files are rejected by the estimation
 		inputFile = "uploaded_" + file.Filename
 		if err := c.SaveFile(file, inputFile); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -54,7 +52,7 @@ func (h *Handlers) EstimateTimeHandler(c *fiber.Ctx) error {
 	}
 
 	// Use the existing EstimateProcessingTime method
-	estimatedTime, err := h.InfluencerApp.EstimateProcessingTime(inputFile)
+	estimatedTime, err := h.InfluencerApp.EstimateProcessingTime(inputFile, options)
 	if err != nil {
 		// Clean up the temp file
 		if needsCleanup {
@@ -62,6 +60,12 @@ func (h *Handlers) EstimateTimeHandler(c *fiber.Ctx) error {
 				log.Printf("Failed to delete temp file %s: %v", inputFile, err)
 			}
 		}
+		if status := inputErrorStatus(err); status != 0 {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		log.Printf("Failed to estimate processing time of %s: %v", inputFile, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to estimate processing time",
 		})
@@ -10,15 +10,19 @@ import (
 type Handlers struct {
 	InfluencerApp *app.InfluencerApp
 	UsersApp      *app.UserApp
+	JobApp        *app.JobApp
+	ArtifactApp   *app.ArtifactApp
 }
 
-func NewHandlers(influencerApp *app.InfluencerApp, usersApp *app.UserApp) (*Handlers, error) {
-	if influencerApp == nil || usersApp == nil {
+func NewHandlers(influencerApp *app.InfluencerApp, usersApp *app.UserApp, jobApp *app.JobApp, artifactApp *app.ArtifactApp) (*Handlers, error) {
+	if influencerApp == nil || usersApp == nil || jobApp == nil || artifactApp == nil {
 		return nil, errors.New("app cannot be nil")
 	}
 	return &Handlers{
 		InfluencerApp: influencerApp,
 		UsersApp:      usersApp,
+		JobApp:        jobApp,
+		ArtifactApp:   artifactApp,
 	}, nil
 }
 
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+)
+
+// inputErrorStatus returns the status of the errors caused by the uploaded file or its options,
+// 0 when err is an internal error
+func inputErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, filemanager.ErrUnsupportedFormat):
+		return fiber.StatusUnsupportedMediaType
+	case errors.Is(err, filemanager.ErrNoLinks):
+		return fiber.StatusUnprocessableEntity
+	case filemanager.IsValidationError(err):
+		return fiber.StatusBadRequest
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+)
+
+func TestInputErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unsupported format", fmt.Errorf("%w: \".pdf\"", filemanager.ErrUnsupportedFormat), fiber.StatusUnsupportedMediaType},
+		{"no links", fmt.Errorf("%w in the file", filemanager.ErrNoLinks), fiber.StatusUnprocessableEntity},
+		{"invalid file", fmt.Errorf("%w: the file is not encoded in UTF-8", filemanager.ErrInvalidFile), fiber.StatusBadRequest},
+		{"invalid options", fmt.Errorf("sheet Links: %w", filemanager.ErrInvalidOptions), fiber.StatusBadRequest},
+		{"internal error", errors.New("disk full"), 0},
+		{"cancelled", context.Canceled, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inputErrorStatus(tt.err); got != tt.want {
+				t.Errorf("inputErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/valyala/fasthttp"
+)
+
+func (h *Handlers) GetJobHandler(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	job, err := h.JobApp.GetJob(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Job not found",
+		})
+	}
+
+	return c.JSON(jobResponse(job))
+}
+
+func (h *Handlers) CancelJobHandler(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	err := h.JobApp.Cancel(userID, c.Params("id"))
+	if errors.Is(err, app.ErrJobFinished) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Job already finished",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Job not found",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Job cancellation requested",
+	})
+}
+
+func (h *Handlers) ListJobsHandler(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	page := c.Query("page", "1")
+	pageNum, err := strconv.Atoi(page)
+	if err != nil || pageNum < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid page number",
+		})
+	}
+	limit := c.Query("limit", "10")
+	limitNum, err := strconv.Atoi(limit)
+	if err != nil || limitNum < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid limit number",
+		})
+	}
+	// Ensure the limit does not exceed a reasonable maximum
+	if limitNum > 100 {
+		limitNum = 100
+	}
+
+	jobs, err := h.JobApp.ListJobs(userID, pageNum, limitNum)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve jobs",
+		})
+	}
+
+	items := make([]fiber.Map, 0, len(jobs.Jobs))
+	for _, job := range jobs.Jobs {
+		items = append(items, jobResponse(job))
+	}
+
+	return c.JSON(fiber.Map{
+		"total_count": jobs.TotalCount,
+		"jobs":        items,
+		"pagination":  jobs.Pagination,
+	})
+}
+
+func jobResponse(job *database.Job) fiber.Map {
+	response := fiber.Map{
+		"id":              job.ID,
+		"status":          job.Status,
+		"progress":        job.Progress(),
+		"total_links":     job.TotalLinks,
+		"processed_links": job.ProcessedLinks,
+		"created_at":      job.CreatedAt,
+		"updated_at":      job.UpdatedAt,
+	}
+	if !job.StartedAt.IsZero() {
+		response["started_at"] = job.StartedAt
+	}
+	if job.IsFinished() {
+		response["finished_at"] = job.FinishedAt
+	}
+	if job.Status == database.JobCompleted {
+		if job.ArtifactID != "" {
+			response["artifact_id"] = job.ArtifactID
+		}
+		if job.Results != nil {
+			response["results"] = job.Results
+		}
+	}
+	if job.Error != "" {
+		response["error"] = job.Error
+	}
+	return response
+}
+
+func (h *Handlers) JobEventsHandler(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "User ID not found in context",
+		})
+	}
+
+	job, err := h.JobApp.GetJob(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Job not found",
+		})
+	}
+
+	history, events, unsubscribe := h.JobApp.SubscribeJobEvents(job)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		for _, event := range history {
+			if err := writeJobEvent(w, event); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(15 * time.Second)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeJobEvent(w, event); err != nil {
+					log.Printf("Client disconnected from job %s events: %v", job.ID, err)
+					return
+				}
+			case <-heartbeat.C:
+				// Comments keep the connection open through proxies
+				if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	}))
+
+	return nil
+}
+
+// writeJobEvent writes the event in the Server-Sent Events format and flushes it
+func writeJobEvent(w *bufio.Writer, event app.JobEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
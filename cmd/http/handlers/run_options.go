@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+)
+
+// parseRunOptions reads the processing options of the upload form:
+//   - mode: "standard" writes one row per link, "enrich" keeps the columns of the uploaded file
+//   - sheets: "all" or comma separated sheet names, the first sheet when empty
+//   - column: header name or letter of the column of the links, every column when empty
+//   - header_row: 1 based row of the headers, 0 when there is none
+//   - format: xlsx, csv, json or jsonl, the format of the output file. The enrich mode writes xlsx only
+func parseRunOptions(c *fiber.Ctx) (app.RunOptions, error) {
+	var opts app.RunOptions
+
+	switch c.FormValue("mode", "standard") {
+	case "standard":
+	case "enrich":
+		opts.Enrich = true
+	default:
+		return opts, errors.New("Unsupported mode. Use standard or enrich")
+	}
+
+	opts.Input.Sheets, opts.Input.AllSheets = filemanager.ParseSheets(c.FormValue("sheets"))
+	opts.Input.Column = strings.TrimSpace(c.FormValue("column"))
+
+	if headerRow := strings.TrimSpace(c.FormValue("header_row")); headerRow != "" {
+		row, err := strconv.Atoi(headerRow)
+		if err != nil || row < 0 {
+			return opts, errors.New("Invalid header_row. Use the number of the header row, or 0 when there is none")
+		}
+		opts.Input.HeaderRow = row
+	}
+
+	format, err := filemanager.ParseFormat(c.FormValue("format"))
+	if err != nil {
+		return opts, errors.New("Unsupported format. Use xlsx, csv, json or jsonl")
+	}
+	if opts.Enrich && format != filemanager.FormatXLSX {
+		return opts, errors.New("The enrich mode writes xlsx files only")
+	}
+	opts.Format = format
+
+	return opts, nil
+}
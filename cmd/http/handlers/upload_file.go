@@ -1,12 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"os"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/solrac97gr/telegram-followers-checker/app"
 )
 
 func (h *Handlers) UploadHandler(c *fiber.Ctx) error {
@@ -23,14 +24,22 @@ func (h *Handlers) UploadHandler(c *fiber.Ctx) error {
 			"error": "Failed to create uploads directory",
 		})
 	}
-	if err := os.MkdirAll("results", os.ModePerm); err != nil {
+	if err := os.MkdirAll(h.ArtifactApp.WorkDir(), os.ModePerm); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create results directory",
 		})
 	}
 
+	options, err := parseRunOptions(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	uniqueID := uuid.New().String()
-	outputFile := "results/" + uniqueID + "_channels_followers.xlsx"
+	// The output file is moved to the storage and downloaded through its artifact once the job completes
+	outputFile := h.ArtifactApp.NewOutputPath(options.Format)
 
 	var inputFile string
 	var needsCleanup bool
@@ -55,14 +64,7 @@ func (h *Handlers) UploadHandler(c *fiber.Ctx) error {
 			})
 		}
 
-		// Validate file type
-		filename := strings.ToLower(file.Filename)
-		if !strings.HasSuffix(filename, ".xlsx") && !strings.HasSuffix(filename, ".xls") && !strings.HasSuffix(filename, ".csv") {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Unsupported file format. Please upload .xlsx, .xls, or .csv files",
-			})
-		}
-
+		// The format is detected by the file manager, unsupported files are rejected when queueing
 		inputFile = "uploads/" + uniqueID + "_uploaded_" + file.Filename
 		if err := c.SaveFile(file, inputFile); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -72,20 +74,33 @@ func (h *Handlers) UploadHandler(c *fiber.Ctx) error {
 		needsCleanup = true
 	}
 
-	// Use the original Run method - FileManager will handle file type detection
-	results := h.InfluencerApp.Run(userID, inputFile, outputFile)
-
-	// Clean up temp file if needed
-	if needsCleanup {
-		if err := os.Remove(inputFile); err != nil {
-			log.Printf("Failed to delete temp file %s: %v", inputFile, err)
+	// Queue the analysis - the job worker cleans up the input file once it is done
+	job, err := h.JobApp.Enqueue(userID, inputFile, outputFile, options)
+	if err != nil {
+		if needsCleanup {
+			if err := os.Remove(inputFile); err != nil {
+				log.Printf("Failed to delete temp file %s: %v", inputFile, err)
+			}
+		}
+		if status := inputErrorStatus(err); status != 0 {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		}
+		if errors.Is(err, app.ErrJobQueueFull) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Too many analyses in progress, please try again later",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to queue analysis",
+		})
 	}
 
-	log.Printf("Processing completed for input: %s", inputFile)
+	log.Printf("Queued job %s for input: %s", job.ID, inputFile)
 
-	return c.JSON(fiber.Map{
-		"outputFile": outputFile,
-		"results":    results,
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"jobId":  job.ID,
+		"status": job.Status,
 	})
 }
@@ -16,12 +16,8 @@ import (
 	"github.com/solrac97gr/telegram-followers-checker/cmd/http/middleware"
 	"github.com/solrac97gr/telegram-followers-checker/config"
 	"github.com/solrac97gr/telegram-followers-checker/database"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/instagram"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/rutube"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/telegram"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/tiktok"
-	"github.com/solrac97gr/telegram-followers-checker/extractors/vk"
-	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+	"github.com/solrac97gr/telegram-followers-checker/storage"
+	"github.com/solrac97gr/telegram-followers-checker/wiring"
 )
 
 const (
@@ -54,14 +50,8 @@ func main() {
 	}
 	_ = repo.DeleteExpiredAnalyses()
 
-	fm := filemanager.NewFileManager()
-	telegramExtractor := telegram.NewTelegramExtractor()
-	rutubeExtractor := rutube.NewRutubeExtractor()
-	vkExtractor := vk.NewVKExtractor()
-	instagramExtractor := instagram.NewInstagramExtractor()
-	tiktokExtractor := tiktok.NewTikTokExtractor()
-
-	influencersApp := app.NewInfluencerApp(repo, fm, telegramExtractor, rutubeExtractor, vkExtractor, instagramExtractor, tiktokExtractor)
+	influencersApp, closeRunner := wiring.NewInfluencerApp(config, repo)
+	defer closeRunner()
 
 	userRepo, err := database.NewUserMongoRepository(mongoClient, config)
 	if err != nil {
@@ -69,7 +59,24 @@ func main() {
 	}
 	usersApp := app.NewUserApp(userRepo, config.JWTSecret)
 
-	hdl, err := handlers.NewHandlers(influencersApp, usersApp)
+	jobRepo, err := database.NewJobMongoRepository(mongoClient, config)
+	if err != nil {
+		log.Fatalf("Error creating job MongoDB repository: %v", err)
+	}
+	artifactRepo, err := database.NewArtifactMongoRepository(mongoClient, config)
+	if err != nil {
+		log.Fatalf("Error creating artifact MongoDB repository: %v", err)
+	}
+	resultsStorage, err := storage.New(config.StorageConfig())
+	if err != nil {
+		log.Fatalf("Error creating results storage: %v", err)
+	}
+	artifactsApp := app.NewArtifactApp(artifactRepo, resultsStorage, config.ResultsDir, config.ArtifactTTL, config.PresignExpiry)
+
+	jobsApp := app.NewJobApp(jobRepo, influencersApp, artifactsApp, config.JobWorkers)
+	jobsApp.Start()
+
+	hdl, err := handlers.NewHandlers(influencersApp, usersApp, jobsApp, artifactsApp)
 	if err != nil {
 		log.Fatalf("Error creating handlers: %v", err)
 	}
@@ -114,15 +121,24 @@ func main() {
 	influencersHandlers := apiv1Group.Group("/influencers")
 	influencersHandlers.Get("/health", hdl.HealthCheckHandler)
 	influencersHandlers.Post("/upload", hdl.UploadHandler)
-	influencersHandlers.Get("/download", hdl.DownloadHandler)
+	influencersHandlers.Get("/artifacts/:id/download", hdl.DownloadHandler)
 	influencersHandlers.Post("/estimate-time", hdl.EstimateTimeHandler)
 	influencersHandlers.Get("/analyses", hdl.AnalysesHandler)
+	influencersHandlers.Get("/jobs", hdl.ListJobsHandler)
+	influencersHandlers.Get("/jobs/:id", hdl.GetJobHandler)
+	influencersHandlers.Get("/jobs/:id/events", hdl.JobEventsHandler)
+	influencersHandlers.Post("/jobs/:id/cancel", hdl.CancelJobHandler)
+	influencersHandlers.Post("/jobs/:id/export", hdl.ExportJobHandler)
 
 	errors := make(chan error, 3)
 	go func() {
 		log.Println("Starting ticker for deleting expired analyses...")
 		TickerDeleteExpiredAnalyses(repo)
 	}()
+	go func() {
+		log.Println("Starting ticker for deleting expired artifacts...")
+		TickerDeleteExpiredArtifacts(artifactsApp)
+	}()
 	go func() {
 		log.Println("Starting Fiber server...")
 		if err := fiberApp.Listen(":3000"); err != nil {
@@ -166,6 +182,21 @@ func TickerDeleteExpiredAnalyses(repo database.InfluencerRepository) {
 	}
 }
 
+func TickerDeleteExpiredArtifacts(artifacts *app.ArtifactApp) {
+	ticker := time.NewTicker(24 * time.Hour) // Adjust the interval as needed
+	defer ticker.Stop()
+
+	for range ticker.C {
+		log.Println("Deleting expired artifacts...")
+		deleted, err := artifacts.DeleteExpired()
+		if err != nil {
+			log.Printf("Error deleting expired artifacts: %v", err)
+		} else {
+			log.Printf("%d expired artifacts deleted successfully", deleted)
+		}
+	}
+}
+
 func TickerDeleteExpiredTokens(repo database.UserRepository) {
 	ticker := time.NewTicker(24 * time.Hour) // Adjust the interval as needed
 	defer ticker.Stop()
@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+	"github.com/solrac97gr/telegram-followers-checker/ratelimit"
+	"github.com/solrac97gr/telegram-followers-checker/storage"
 )
 
 type Config struct {
@@ -14,6 +18,41 @@ type Config struct {
 	UsersDBName       string `envconfig:"USERS_DB"`
 	InstagramUsername string `envconfig:"INSTAGRAM_USERNAME"`
 	InstagramPassword string `envconfig:"INSTAGRAM_PASSWORD"`
+	JobWorkers        int    `envconfig:"JOB_WORKERS" default:"2"`
+	// Workers of every job, the limits of the platforms still apply
+	ExtractionWorkers   int `envconfig:"EXTRACTION_WORKERS" default:"8"`
+	RegistrationWorkers int `envconfig:"REGISTRATION_WORKERS" default:"10"`
+	// Persistent browser workers running the scripts, 0 starts a new node process for every script
+	BrowserWorkers        int           `envconfig:"BROWSER_WORKERS" default:"4"`
+	BrowserWorkerCommand  string        `envconfig:"BROWSER_WORKER_COMMAND" default:"node scripts/worker.js"`
+	BrowserHealthInterval time.Duration `envconfig:"BROWSER_HEALTH_INTERVAL" default:"30s"`
+	// Read the static t.me preview pages over HTTP, the browser is only used when they can't be parsed
+	TelegramHTTP bool `envconfig:"TELEGRAM_HTTP" default:"true"`
+	// Result files can be downloaded by their owner until they expire, they are deleted afterwards
+	ArtifactTTL time.Duration `envconfig:"ARTIFACT_TTL" default:"720h"`
+
+	// Storage of the result files, local keeps them in the results directory and s3 in a bucket
+	// of an S3 compatible service. The results directory is also where the files are written first.
+	StorageBackend string `envconfig:"STORAGE_BACKEND" default:"local"`
+	ResultsDir     string `envconfig:"RESULTS_DIR" default:"results"`
+	S3Endpoint     string `envconfig:"S3_ENDPOINT"` // host:port, without the scheme
+	S3AccessKey    string `envconfig:"S3_ACCESS_KEY"`
+	S3SecretKey    string `envconfig:"S3_SECRET_KEY"`
+	S3Bucket       string `envconfig:"S3_BUCKET" default:"results"`
+	S3Region       string `envconfig:"S3_REGION"`
+	S3UseSSL       bool   `envconfig:"S3_USE_SSL" default:"false"`
+	// Lifetime of the presigned download URLs of the s3 storage, 0 streams the files through the server
+	PresignExpiry time.Duration `envconfig:"PRESIGN_EXPIRY" default:"15m"`
+
+	// Retries of the transient extraction failures, keyed by platform ("default" applies to the others)
+	RetryMaxAttempts map[string]int           `envconfig:"RETRY_MAX_ATTEMPTS" default:"default:3,vk:5,gosuslugi:5"`
+	RetryBaseDelay   map[string]time.Duration `envconfig:"RETRY_BASE_DELAY" default:"default:2s,vk:5s,gosuslugi:5s"`
+	RetryMaxDelay    time.Duration            `envconfig:"RETRY_MAX_DELAY" default:"1m"`
+	RetryJitter      float64                  `envconfig:"RETRY_JITTER" default:"0.2"`
+
+	// Limits shared by every job, keyed by platform ("default" applies to the others)
+	RateLimits     map[string]float64 `envconfig:"RATE_LIMITS" default:"vk:20,tiktok:20,instagram:10,telegram:60,rutube:30,youtube:20,ok:20,dzen:20,gosuslugi:30"` // Requests per minute
+	MaxConcurrency map[string]int     `envconfig:"MAX_CONCURRENCY" default:"vk:2,tiktok:2,instagram:1,telegram:4,rutube:3,youtube:2,ok:2,dzen:2,gosuslugi:10"`
 }
 
 func NewConfig() (*Config, error) {
@@ -25,3 +64,64 @@ func NewConfig() (*Config, error) {
 	}
 	return &cfg, nil
 }
+
+// RetryPolicies builds the retry policy of every configured platform
+func (c *Config) RetryPolicies() extractor.RetryPolicies {
+	policies := extractor.RetryPolicies{}
+	policy := func(platform string) extractor.RetryPolicy {
+		if p, ok := policies[platform]; ok {
+			return p
+		}
+		p := extractor.DefaultRetryPolicy
+		p.MaxDelay = c.RetryMaxDelay
+		p.Jitter = c.RetryJitter
+		if defaultDelay, ok := c.RetryBaseDelay[extractor.DefaultRetryPlatform]; ok {
+			p.BaseDelay = defaultDelay
+		}
+		if defaultAttempts, ok := c.RetryMaxAttempts[extractor.DefaultRetryPlatform]; ok {
+			p.MaxAttempts = defaultAttempts
+		}
+		return p
+	}
+	for platform, attempts := range c.RetryMaxAttempts {
+		p := policy(platform)
+		p.MaxAttempts = attempts
+		policies[platform] = p
+	}
+	for platform, delay := range c.RetryBaseDelay {
+		p := policy(platform)
+		p.BaseDelay = delay
+		policies[platform] = p
+	}
+	return policies
+}
+
+// StorageConfig builds the configuration of the storage of the result files
+func (c *Config) StorageConfig() storage.Config {
+	return storage.Config{
+		Backend:     c.StorageBackend,
+		Dir:         c.ResultsDir,
+		S3Endpoint:  c.S3Endpoint,
+		S3AccessKey: c.S3AccessKey,
+		S3SecretKey: c.S3SecretKey,
+		S3Bucket:    c.S3Bucket,
+		S3Region:    c.S3Region,
+		S3UseSSL:    c.S3UseSSL,
+	}
+}
+
+// Limits builds the rate and concurrency limit of every configured platform
+func (c *Config) Limits() map[string]ratelimit.Limit {
+	limits := make(map[string]ratelimit.Limit)
+	for platform, requestsPerMinute := range c.RateLimits {
+		limit := limits[platform]
+		limit.RequestsPerMinute = requestsPerMinute
+		limits[platform] = limit
+	}
+	for platform, maxConcurrent := range c.MaxConcurrency {
+		limit := limits[platform]
+		limit.MaxConcurrent = maxConcurrent
+		limits[platform] = limit
+	}
+	return limits
+}
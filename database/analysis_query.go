@@ -0,0 +1,213 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidQuery  = errors.New("invalid analysis query")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Fields the analyses can be sorted by
+const (
+	SortByCreatedAt   = "created_at"
+	SortByFollowers   = "followers_count"
+	SortByChannelName = "channel_name"
+)
+
+// AnalysisFilter selects the analyses of a search, the zero fields don't filter
+type AnalysisFilter struct {
+	UserID             string // Owner of the analyses, every owner when empty
+	Platform           string
+	RegistrationStatus Status
+	MinFollowers       *int64
+	MaxFollowers       *int64
+	CreatedFrom        time.Time
+	CreatedTo          time.Time // Exclusive
+	ChannelName        string    // Case insensitive substring of the channel name
+}
+
+// AnalysisQuery is a search of the analyses, a page at a time
+type AnalysisQuery struct {
+	Filter    AnalysisFilter
+	SortBy    string // created_at, followers_count or channel_name, created_at when empty
+	Ascending bool   // Newest, largest or last names first by default
+	Cursor    string // NextCursor of the previous page, the first page when empty
+	Limit     int
+}
+
+// AnalysisPage is a page of a search of the analyses
+type AnalysisPage struct {
+	TotalCount int64                 `json:"total_count"` // Analyses matching the filter on every page
+	Analyses   []*InfluencerAnalysis `json:"analyses"`
+	NextCursor string                `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// analysisCursor is the position of the last analysis of a page. The sort is kept with it,
+// so a cursor can't be used with another sort.
+type analysisCursor struct {
+	SortBy    string          `json:"s"`
+	Ascending bool            `json:"a,omitempty"`
+	Value     json.RawMessage `json:"v"`
+	ID        string          `json:"id"`
+}
+
+// Validate checks the sort and the ranges of the query
+func (q AnalysisQuery) Validate() error {
+	switch q.SortBy {
+	case "", SortByCreatedAt, SortByFollowers, SortByChannelName:
+	default:
+		return fmt.Errorf("%w: unknown sort field %q, use %s, %s or %s", ErrInvalidQuery, q.SortBy, SortByCreatedAt, SortByFollowers, SortByChannelName)
+	}
+	f := q.Filter
+	if f.MinFollowers != nil && f.MaxFollowers != nil && *f.MinFollowers > *f.MaxFollowers {
+		return fmt.Errorf("%w: min followers is greater than max followers", ErrInvalidQuery)
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && !f.CreatedFrom.Before(f.CreatedTo) {
+		return fmt.Errorf("%w: created from is not before created to", ErrInvalidQuery)
+	}
+	if q.Limit < 1 {
+		return fmt.Errorf("%w: limit must be positive", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// sortField returns the field the query is sorted by
+func (q AnalysisQuery) sortField() string {
+	if q.SortBy == "" {
+		return SortByCreatedAt
+	}
+	return q.SortBy
+}
+
+// mongoFilter builds the filter of the analyses that have not expired
+func (f AnalysisFilter) mongoFilter() bson.M {
+	filter := bson.M{"expiration_date": bson.M{"$gt": time.Now()}}
+	if f.UserID != "" {
+		filter["user_id"] = f.UserID
+	}
+	if f.Platform != "" {
+		filter["platform"] = f.Platform
+	}
+	if f.RegistrationStatus != "" {
+		filter["registration_status"] = f.RegistrationStatus
+	}
+	followers := bson.M{}
+	if f.MinFollowers != nil {
+		followers["$gte"] = *f.MinFollowers
+	}
+	if f.MaxFollowers != nil {
+		followers["$lte"] = *f.MaxFollowers
+	}
+	if len(followers) > 0 {
+		filter["followers_count"] = followers
+	}
+	created := bson.M{}
+	if !f.CreatedFrom.IsZero() {
+		created["$gte"] = f.CreatedFrom
+	}
+	if !f.CreatedTo.IsZero() {
+		created["$lt"] = f.CreatedTo
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	if f.ChannelName != "" {
+		filter["channel_name"] = primitive.Regex{Pattern: regexp.QuoteMeta(f.ChannelName), Options: "i"}
+	}
+	return filter
+}
+
+// mongoSort sorts by the field of the query, ties are broken by ID so the cursors are stable
+func (q AnalysisQuery) mongoSort() bson.D {
+	direction := -1
+	if q.Ascending {
+		direction = 1
+	}
+	return bson.D{{Key: q.sortField(), Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// afterCursor builds the filter of the analyses that come after the cursor in the sort of the query
+func (q AnalysisQuery) afterCursor() (bson.M, error) {
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor analysisCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != q.sortField() || cursor.Ascending != q.Ascending {
+		return nil, fmt.Errorf("%w: the cursor belongs to another sort", ErrInvalidCursor)
+	}
+
+	var value any
+	switch cursor.SortBy {
+	case SortByCreatedAt:
+		var createdAt time.Time
+		err = json.Unmarshal(cursor.Value, &createdAt)
+		value = createdAt
+	case SortByFollowers:
+		var followers int64
+		err = json.Unmarshal(cursor.Value, &followers)
+		value = followers
+	default:
+		var name string
+		err = json.Unmarshal(cursor.Value, &name)
+		value = name
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	// The stored IDs are ObjectIDs, decoded as hex strings
+	var id any = cursor.ID
+	if objectID, err := primitive.ObjectIDFromHex(cursor.ID); err == nil {
+		id = objectID
+	}
+
+	operator := "$lt"
+	if q.Ascending {
+		operator = "$gt"
+	}
+	field := q.sortField()
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{operator: value}},
+		bson.M{field: value, "_id": bson.M{operator: id}},
+	}}, nil
+}
+
+// nextCursor returns the cursor of the page that follows the analysis
+func (q AnalysisQuery) nextCursor(last *InfluencerAnalysis) (string, error) {
+	var value any
+	switch q.sortField() {
+	case SortByCreatedAt:
+		value = last.CreatedAt
+	case SortByFollowers:
+		value = last.FollowersCount
+	default:
+		value = last.ChannelName
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(analysisCursor{
+		SortBy:    q.sortField(),
+		Ascending: q.Ascending,
+		Value:     raw,
+		ID:        last.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
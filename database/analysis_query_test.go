@@ -0,0 +1,136 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAnalysisCursorRoundTrip(t *testing.T) {
+	last := &InfluencerAnalysis{
+		ID:             "65f0c1a2b3c4d5e6f7a8b9c0",
+		ChannelName:    "Durov's Channel",
+		FollowersCount: 8871934,
+		CreatedAt:      time.Date(2025, 3, 4, 5, 6, 7, 8_000_000, time.UTC),
+	}
+	objectID, _ := primitive.ObjectIDFromHex(last.ID)
+
+	tests := []struct {
+		sortBy    string
+		ascending bool
+		field     string
+		value     any
+		operator  string
+	}{
+		{"", false, SortByCreatedAt, last.CreatedAt, "$lt"},
+		{SortByCreatedAt, true, SortByCreatedAt, last.CreatedAt, "$gt"},
+		{SortByFollowers, false, SortByFollowers, last.FollowersCount, "$lt"},
+		{SortByChannelName, true, SortByChannelName, last.ChannelName, "$gt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			query := AnalysisQuery{SortBy: tt.sortBy, Ascending: tt.ascending, Limit: 10}
+			cursor, err := query.nextCursor(last)
+			if err != nil {
+				t.Fatalf("nextCursor: %v", err)
+			}
+			query.Cursor = cursor
+			after, err := query.afterCursor()
+			if err != nil {
+				t.Fatalf("afterCursor: %v", err)
+			}
+
+			want := bson.M{"$or": bson.A{
+				bson.M{tt.field: bson.M{tt.operator: tt.value}},
+				bson.M{tt.field: tt.value, "_id": bson.M{tt.operator: objectID}},
+			}}
+			if !reflect.DeepEqual(after, want) {
+				t.Errorf("afterCursor = %v, want %v", after, want)
+			}
+		})
+	}
+}
+
+func TestAnalysisCursorErrors(t *testing.T) {
+	last := &InfluencerAnalysis{ID: "65f0c1a2b3c4d5e6f7a8b9c0", FollowersCount: 10}
+	followers := AnalysisQuery{SortBy: SortByFollowers, Limit: 10}
+	cursor, err := followers.nextCursor(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query AnalysisQuery
+	}{
+		{"not base64", AnalysisQuery{Cursor: "!!!", Limit: 10}},
+		{"not json", AnalysisQuery{Cursor: "bm90IGpzb24", Limit: 10}},
+		{"other sort field", AnalysisQuery{SortBy: SortByCreatedAt, Cursor: cursor, Limit: 10}},
+		{"other direction", AnalysisQuery{SortBy: SortByFollowers, Ascending: true, Cursor: cursor, Limit: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.query.afterCursor(); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("afterCursor error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestAnalysisQueryValidate(t *testing.T) {
+	small, large := int64(10), int64(1000)
+	now := time.Now()
+	tests := []struct {
+		name    string
+		query   AnalysisQuery
+		wantErr bool
+	}{
+		{"defaults", AnalysisQuery{Limit: 10}, false},
+		{"followers range", AnalysisQuery{Limit: 10, Filter: AnalysisFilter{MinFollowers: &small, MaxFollowers: &large}}, false},
+		{"date range", AnalysisQuery{Limit: 10, Filter: AnalysisFilter{CreatedFrom: now.Add(-time.Hour), CreatedTo: now}}, false},
+		{"unknown sort", AnalysisQuery{Limit: 10, SortBy: "password"}, true},
+		{"inverted followers", AnalysisQuery{Limit: 10, Filter: AnalysisFilter{MinFollowers: &large, MaxFollowers: &small}}, true},
+		{"inverted dates", AnalysisQuery{Limit: 10, Filter: AnalysisFilter{CreatedFrom: now, CreatedTo: now.Add(-time.Hour)}}, true},
+		{"no limit", AnalysisQuery{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnalysisFilterMongoFilter(t *testing.T) {
+	min := int64(1000)
+	filter := AnalysisFilter{
+		UserID:             "alice",
+		Platform:           "telegram",
+		RegistrationStatus: Registered,
+		MinFollowers:       &min,
+		ChannelName:        "go.dev (news)",
+	}.mongoFilter()
+
+	if filter["user_id"] != "alice" || filter["platform"] != "telegram" || filter["registration_status"] != Registered {
+		t.Errorf("mongoFilter = %v, want the owner, the platform and the status", filter)
+	}
+	if followers := filter["followers_count"].(bson.M); followers["$gte"] != min || followers["$lte"] != nil {
+		t.Errorf("followers filter = %v, want only $gte %d", followers, min)
+	}
+	// The name is a substring, its regex characters are escaped
+	if regex := filter["channel_name"].(primitive.Regex); regex.Pattern != `go\.dev \(news\)` || regex.Options != "i" {
+		t.Errorf("channel filter = %v, want the quoted case insensitive name", regex)
+	}
+	if _, ok := filter["expiration_date"]; !ok {
+		t.Errorf("mongoFilter = %v, want the expired analyses left out", filter)
+	}
+	if _, ok := (AnalysisFilter{}).mongoFilter()["user_id"]; ok {
+		t.Errorf("an empty owner must not filter the analyses")
+	}
+}
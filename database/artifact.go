@@ -0,0 +1,37 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Artifact is a result file owned by a user, it is downloaded by ID and never by path
+type Artifact struct {
+	ID         string    `json:"id" bson:"_id"`
+	UserID     string    `json:"user_id" bson:"user_id"` // ID of the user who can download the file
+	JobID      string    `json:"job_id" bson:"job_id"`
+	StorageKey string    `json:"-" bson:"storage_key"`       // Key of the file in the results storage, never sent to the clients
+	FileName   string    `json:"file_name" bson:"file_name"` // Name of the file when it is downloaded
+	Format     string    `json:"format" bson:"format"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+func NewArtifact(userID, jobID, storageKey, fileName, format string, ttl time.Duration) *Artifact {
+	return &Artifact{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		JobID:      jobID,
+		StorageKey: storageKey,
+		FileName:   fileName,
+		Format:     format,
+		ExpiresAt:  time.Now().Add(ttl),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsExpired reports whether the artifact can no longer be downloaded
+func (a *Artifact) IsExpired() bool {
+	return !time.Now().Before(a.ExpiresAt)
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ArtifactsCollectionName = "influencer-artifacts"
+)
+
+type ArtifactMongoRepository struct {
+	client *mongo.Client
+	config *config.Config
+}
+
+var _ ArtifactRepository = (*ArtifactMongoRepository)(nil)
+
+func NewArtifactMongoRepository(client *mongo.Client, config *config.Config) (*ArtifactMongoRepository, error) {
+	if client == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	return &ArtifactMongoRepository{
+		client: client,
+		config: config,
+	}, nil
+}
+
+func (repo *ArtifactMongoRepository) collection() *mongo.Collection {
+	return repo.client.Database(repo.config.InfluencersDBName).Collection(ArtifactsCollectionName)
+}
+
+// SaveArtifact implements ArtifactRepository.
+func (repo *ArtifactMongoRepository) SaveArtifact(artifact *Artifact) error {
+	ctx := context.Background()
+	_, err := repo.collection().InsertOne(ctx, artifact)
+	return err
+}
+
+// GetArtifactByID implements ArtifactRepository.
+func (repo *ArtifactMongoRepository) GetArtifactByID(artifactID string) (*Artifact, error) {
+	return repo.findOne(bson.M{"_id": artifactID})
+}
+
+// GetArtifactByJob implements ArtifactRepository.
+func (repo *ArtifactMongoRepository) GetArtifactByJob(jobID string, format string) (*Artifact, error) {
+	// The newest file of the format is the one kept when it was exported several times
+	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
+	return repo.findOne(bson.M{"job_id": jobID, "format": format}, opts)
+}
+
+// GetExpiredArtifacts implements ArtifactRepository.
+func (repo *ArtifactMongoRepository) GetExpiredArtifacts(limit int) ([]*Artifact, error) {
+	ctx := context.Background()
+	filter := bson.M{"expires_at": bson.M{"$lt": time.Now()}}
+	cursor, err := repo.collection().Find(ctx, filter, options.Find().SetSort(bson.M{"expires_at": 1}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var artifacts []*Artifact
+	if err := cursor.All(ctx, &artifacts); err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+// DeleteArtifact implements ArtifactRepository.
+func (repo *ArtifactMongoRepository) DeleteArtifact(artifactID string) error {
+	ctx := context.Background()
+	_, err := repo.collection().DeleteOne(ctx, bson.M{"_id": artifactID})
+	return err
+}
+
+func (repo *ArtifactMongoRepository) findOne(filter bson.M, opts ...*options.FindOneOptions) (*Artifact, error) {
+	ctx := context.Background()
+	result := repo.collection().FindOne(ctx, filter, opts...)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var artifact Artifact
+	if err := result.Decode(&artifact); err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
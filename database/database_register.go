@@ -1,7 +1,6 @@
 package database
 
 import (
-	"strconv"
 	"time"
 )
 
@@ -14,52 +13,46 @@ const (
 )
 
 type InfluencerAnalysis struct {
-	ID                 string    `json:"id" bson:"_id,omitempty"`
-	UserID             string    `json:"user_id" bson:"user_id"` // ID of the user who created the analysis
-	ChannelName        string    `json:"channel_name" bson:"channel_name"`
-	FollowersCount     int       `json:"followers_count" bson:"followers_count"`
-	Link               string    `json:"link" bson:"link"`
-	Platform           string    `json:"platform" bson:"platform"`
-	RegistrationStatus Status    `json:"registration_status" bson:"registration_status"`
-	ExpirationDate     time.Time `json:"expiration_date" bson:"expiration_date"`
-	CreatedAt          time.Time `json:"created_at" bson:"created_at"`
+	ID                   string    `json:"id" bson:"_id,omitempty"`
+	UserID               string    `json:"user_id" bson:"user_id"` // ID of the user who created the analysis
+	ChannelName          string    `json:"channel_name" bson:"channel_name"`
+	FollowersCount       int64     `json:"followers_count" bson:"followers_count"`
+	FollowersText        string    `json:"followers_text,omitempty" bson:"followers_text,omitempty"` // Raw text scraped from the page
+	VideosCount          int64     `json:"videos_count,omitempty" bson:"videos_count,omitempty"`
+	Link                 string    `json:"link" bson:"link"`
+	Platform             string    `json:"platform" bson:"platform"`
+	ChannelKey           string    `json:"channel_key,omitempty" bson:"channel_key,omitempty"` // "platform:channel", shared by every link of the channel
+	RegistrationStatus   Status    `json:"registration_status" bson:"registration_status"`
+	Attempts             int       `json:"attempts,omitempty" bson:"attempts,omitempty"`                           // Attempts used by the extraction
+	RegistrationAttempts int       `json:"registration_attempts,omitempty" bson:"registration_attempts,omitempty"` // Attempts used by the registration check
+	ReusedFrom           string    `json:"reused_from,omitempty" bson:"reused_from,omitempty"`                     // ID of the original analysis of another user this copy was made from, never another copy
+	ExpirationDate       time.Time `json:"expiration_date" bson:"expiration_date"`
+	CreatedAt            time.Time `json:"created_at" bson:"created_at"`
 }
 
-func NewInfluencerAnalysis(userID, channelName, link, platform string, followersCount string, registrationStatus string) *InfluencerAnalysis {
-	var followersCountInt int
-	followersCountInt, err := strconv.Atoi(followersCount)
-	if err != nil {
-		followersCountInt = 0 // Default to 0 if conversion fails
-	}
-
+func NewInfluencerAnalysis(userID, channelName, link, platform string, followersCount int64, followersText string, registrationStatus string) *InfluencerAnalysis {
 	return &InfluencerAnalysis{
-		UserID:         userID,
-		ChannelName:    channelName,
-		FollowersCount: followersCountInt,
-		Link:           link,
-		Platform:       platform,
-		RegistrationStatus: func(rs string) Status {
-			switch rs {
-			case "registered 🟢":
-				return Registered
-			case "not registered 🔴":
-				return NotRegistered
-			default:
-				return NotApply // Default to NotApply if not registered or not applicable
-			}
-		}(registrationStatus),
-		ExpirationDate: time.Now().Add(30 * 24 * time.Hour), // Default expiration date set to 15 days from now
-		CreatedAt:      time.Now(),
+		UserID:             userID,
+		ChannelName:        channelName,
+		FollowersCount:     followersCount,
+		FollowersText:      followersText,
+		Link:               link,
+		Platform:           platform,
+		RegistrationStatus: ParseStatus(registrationStatus),
+		ExpirationDate:     time.Now().Add(30 * 24 * time.Hour), // Default expiration date set to 15 days from now
+		CreatedAt:          time.Now(),
 	}
 }
 
-func (dr *InfluencerAnalysis) ToExcelRow() []string {
-	return []string{
-		dr.ChannelName,
-		strconv.Itoa(dr.FollowersCount),
-		dr.Link,
-		dr.Platform,
-		dr.RegistrationStatus.String(),
+// ParseStatus converts the display value of a status back to a Status
+func ParseStatus(status string) Status {
+	switch status {
+	case "registered 🟢":
+		return Registered
+	case "not registered 🔴":
+		return NotRegistered
+	default:
+		return NotApply // Default to NotApply if not registered or not applicable
 	}
 }
 
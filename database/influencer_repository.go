@@ -1,19 +1,39 @@
 package database
 
-type AllInfluencerAnalysis struct {
-	TotalCount int64                 `json:"total_count" bson:"total_count"`
-	Analyses   []*InfluencerAnalysis `json:"analyses" bson:"analyses"`
+type InfluencerRepository interface {
+	SaveInfluencerAnalysis(influencer *InfluencerAnalysis) error
+	GetInfluencerAnalysisByLink(link string) (*InfluencerAnalysis, error)
+	GetInfluencerAnalysisByChannelKey(channelKey string) (*InfluencerAnalysis, error)
+	GetUserInfluencerAnalysisByChannelKey(userID string, channelKey string) (*InfluencerAnalysis, error)
+	SaveReusedAnalysis(analysis *InfluencerAnalysis) error // Replaces the copy the user already has of the channel
+	DeleteExpiredAnalyses() error
+	SearchInfluencerAnalyses(query AnalysisQuery) (AnalysisPage, error)
+}
+
+type AllJobs struct {
+	TotalCount int64  `json:"total_count" bson:"total_count"`
+	Jobs       []*Job `json:"jobs" bson:"jobs"`
 	Pagination struct {
 		Page  int64 `json:"page" bson:"page"`
 		Limit int64 `json:"limit" bson:"limit"`
 	} `json:"pagination" bson:"pagination"`
 }
 
-type InfluencerRepository interface {
-	SaveInfluencerAnalysis(influencer *InfluencerAnalysis) error
-	GetInfluencerAnalysisByLink(link string) (*InfluencerAnalysis, error)
-	DeleteExpiredAnalyses() error
-	GetAllInfluencerAnalyses(page int, limit int) (AllInfluencerAnalysis, error)
+type JobRepository interface {
+	SaveJob(job *Job) error
+	UpdateJob(job *Job) error
+	UpdateJobProgress(jobID string, processed int, total int) error
+	GetJobByID(jobID string) (*Job, error)
+	GetJobsByUserID(userID string, page int, limit int) (AllJobs, error)
+	GetJobsByStatus(statuses ...JobStatus) ([]*Job, error)
+}
+
+type ArtifactRepository interface {
+	SaveArtifact(artifact *Artifact) error
+	GetArtifactByID(artifactID string) (*Artifact, error)
+	GetArtifactByJob(jobID string, format string) (*Artifact, error) // Newest artifact of the job in the format
+	GetExpiredArtifacts(limit int) ([]*Artifact, error)
+	DeleteArtifact(artifactID string) error
 }
 
 type UserRepository interface {
@@ -37,9 +37,9 @@ func (repo *MongoRepository) save(ctx context.Context, data interface{}) error {
 	return err
 }
 
-func (repo *MongoRepository) findOne(ctx context.Context, filter interface{}) (*InfluencerAnalysis, error) {
+func (repo *MongoRepository) findOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) (*InfluencerAnalysis, error) {
 	collection := repo.client.Database(repo.config.InfluencersDBName).Collection(InfluencersCollectionName)
-	result := collection.FindOne(ctx, filter)
+	result := collection.FindOne(ctx, filter, opts...)
 	if result.Err() != nil {
 		return nil, result.Err()
 	}
@@ -64,6 +64,43 @@ func (repo *MongoRepository) GetInfluencerAnalysisByLink(link string) (*Influenc
 	return analysis, nil
 }
 
+// GetInfluencerAnalysisByChannelKey returns the latest analysis of the channel that has not expired
+func (repo *MongoRepository) GetInfluencerAnalysisByChannelKey(channelKey string) (*InfluencerAnalysis, error) {
+	ctx := context.Background()
+	filter := bson.M{"channel_key": channelKey, "expiration_date": bson.M{"$gt": time.Now()}}
+	analysis, err := repo.findOne(ctx, filter, options.FindOne().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// GetUserInfluencerAnalysisByChannelKey returns the latest analysis of the channel of the user that has not expired
+func (repo *MongoRepository) GetUserInfluencerAnalysisByChannelKey(userID string, channelKey string) (*InfluencerAnalysis, error) {
+	ctx := context.Background()
+	filter := bson.M{"user_id": userID, "channel_key": channelKey, "expiration_date": bson.M{"$gt": time.Now()}}
+	analysis, err := repo.findOne(ctx, filter, options.FindOne().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// SaveReusedAnalysis stores the copy of an analysis of another user. A user keeps a single copy of a channel,
+// channels without a key are matched by link, so reusing the channel again replaces it.
+func (repo *MongoRepository) SaveReusedAnalysis(analysis *InfluencerAnalysis) error {
+	ctx := context.Background()
+	collection := repo.client.Database(repo.config.InfluencersDBName).Collection(InfluencersCollectionName)
+	filter := bson.M{"user_id": analysis.UserID, "reused_from": bson.M{"$exists": true}}
+	if analysis.ChannelKey != "" {
+		filter["channel_key"] = analysis.ChannelKey
+	} else {
+		filter["link"] = analysis.Link
+	}
+	_, err := collection.ReplaceOne(ctx, filter, analysis, options.Replace().SetUpsert(true))
+	return err
+}
+
 func (repo *MongoRepository) DeleteExpiredAnalyses() error {
 	ctx := context.Background()
 	collection := repo.client.Database(repo.config.InfluencersDBName).Collection(InfluencersCollectionName)
@@ -72,15 +109,29 @@ func (repo *MongoRepository) DeleteExpiredAnalyses() error {
 	return err
 }
 
-func (repo *MongoRepository) GetAllInfluencerAnalyses(page int, limit int) (AllInfluencerAnalysis, error) {
+// SearchInfluencerAnalyses returns a page of the analyses matching the query that have not expired
+func (repo *MongoRepository) SearchInfluencerAnalyses(query AnalysisQuery) (AnalysisPage, error) {
+	if err := query.Validate(); err != nil {
+		return AnalysisPage{}, err
+	}
 	ctx := context.Background()
 	collection := repo.client.Database(repo.config.InfluencersDBName).Collection(InfluencersCollectionName)
 
-	skip := (page - 1) * limit
-	filter := bson.M{"expiration_date": bson.M{"$gt": time.Now()}}
-	cursor, err := collection.Find(ctx, filter, options.Find().SetSkip(int64(skip)).SetLimit(int64(limit)))
+	filter := query.Filter.mongoFilter()
+	pageFilter := filter
+	if query.Cursor != "" {
+		after, err := query.afterCursor()
+		if err != nil {
+			return AnalysisPage{}, err
+		}
+		pageFilter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	// One more analysis than the limit tells whether there is a next page
+	opts := options.Find().SetSort(query.mongoSort()).SetLimit(int64(query.Limit + 1))
+	cursor, err := collection.Find(ctx, pageFilter, opts)
 	if err != nil {
-		return AllInfluencerAnalysis{}, err
+		return AnalysisPage{}, err
 	}
 	defer func() {
 		if err := cursor.Close(ctx); err != nil {
@@ -88,29 +139,22 @@ func (repo *MongoRepository) GetAllInfluencerAnalyses(page int, limit int) (AllI
 		}
 	}()
 
-	var analyses []*InfluencerAnalysis
-	for cursor.Next(ctx) {
-		var analysis InfluencerAnalysis
-		if err := cursor.Decode(&analysis); err != nil {
-			return AllInfluencerAnalysis{}, err
-		}
-		analyses = append(analyses, &analysis)
+	analyses := make([]*InfluencerAnalysis, 0, query.Limit)
+	if err := cursor.All(ctx, &analyses); err != nil {
+		return AnalysisPage{}, err
 	}
 
 	totalCount, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return AllInfluencerAnalysis{}, err
+		return AnalysisPage{}, err
 	}
 
-	return AllInfluencerAnalysis{
-		TotalCount: totalCount,
-		Analyses:   analyses,
-		Pagination: struct {
-			Page  int64 `json:"page" bson:"page"`
-			Limit int64 `json:"limit" bson:"limit"`
-		}{
-			Page:  int64(page),
-			Limit: int64(limit),
-		},
-	}, nil
+	page := AnalysisPage{TotalCount: totalCount, Analyses: analyses}
+	if len(analyses) > query.Limit {
+		page.Analyses = analyses[:query.Limit]
+		if page.NextCursor, err = query.nextCursor(page.Analyses[query.Limit-1]); err != nil {
+			return AnalysisPage{}, err
+		}
+	}
+	return page, nil
 }
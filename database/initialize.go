@@ -15,7 +15,7 @@ import (
 type Index struct {
 	Field      string `json:"field"`
 	Collection string `json:"collection"`
-	Type       string `json:"type"` // e.g., "text", "hashed", etc.
+	Type       string `json:"type"` // e.g., "text", "hashed", "compound" (Field lists the fields separated by commas), etc.
 }
 
 type Databases struct {
@@ -34,10 +34,27 @@ func NewDatabaseConfig(config *config.Config) ([]Databases, error) {
 			Name: config.InfluencersDBName,
 			Collections: []string{
 				InfluencersCollectionName,
+				JobsCollectionName,
+				ArtifactsCollectionName,
 			},
 			Indexes: []Index{
 				{Field: "link", Collection: InfluencersCollectionName, Type: "text"},
+				{Field: "channel_key", Collection: InfluencersCollectionName, Type: "hashed"},
 				{Field: "user_id", Collection: InfluencersCollectionName, Type: "hashed"},
+				// Analyses of a channel reused by a user
+				{Field: "user_id,channel_key", Collection: InfluencersCollectionName, Type: "compound"},
+				// Searches of the analyses, scoped to a user or not, in every sort
+				{Field: "user_id,created_at,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "user_id,followers_count,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "user_id,channel_name,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "user_id,platform,registration_status,created_at", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "created_at,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "followers_count,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "channel_name,_id", Collection: InfluencersCollectionName, Type: "compound"},
+				{Field: "user_id", Collection: JobsCollectionName, Type: "hashed"},
+				{Field: "status", Collection: JobsCollectionName, Type: "hashed"},
+				{Field: "job_id", Collection: ArtifactsCollectionName, Type: "hashed"},
+				{Field: "expires_at", Collection: ArtifactsCollectionName, Type: "ascending"},
 			},
 		},
 		{
@@ -111,6 +128,16 @@ func createIndexIfNotExists(db *mongo.Client, databaseName, collectionName, fiel
 			Keys:    bson.M{field: "hashed"},
 			Options: options.Index().SetName(field + "_hashed_index"),
 		}
+	case "compound":
+		// Ascending index on the comma separated fields, it serves the sorts in both directions
+		keys := bson.D{}
+		for _, key := range strings.Split(field, ",") {
+			keys = append(keys, bson.E{Key: key, Value: 1})
+		}
+		indexModel = mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(strings.ReplaceAll(field, ",", "_") + "_index"),
+		}
 	default:
 		// Default to ascending index
 		indexModel = mongo.IndexModel{
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// JobOptions are the processing options chosen when the file was uploaded
+type JobOptions struct {
+	Enrich    bool     `json:"enrich,omitempty" bson:"enrich,omitempty"`         // Keep the columns of the input file in the output
+	Sheets    []string `json:"sheets,omitempty" bson:"sheets,omitempty"`         // Sheets to read, the first one when empty
+	AllSheets bool     `json:"all_sheets,omitempty" bson:"all_sheets,omitempty"` // Read every sheet
+	Column    string   `json:"column,omitempty" bson:"column,omitempty"`         // Header name or letter of the column of the links
+	HeaderRow int      `json:"header_row,omitempty" bson:"header_row,omitempty"` // 1 based row of the headers, 0 when there is none
+	Format    string   `json:"format,omitempty" bson:"format,omitempty"`         // Format of the output file, xlsx when empty
+}
+
+// JobResult is the result of a link of a completed job, with the fields the displayed rows leave out
+type JobResult struct {
+	ChannelName        string    `bson:"channel_name"`
+	FollowersCount     *int64    `bson:"followers_count,omitempty"` // nil when the followers could not be read
+	Link               string    `bson:"link"`
+	Platform           string    `bson:"platform"`
+	RegistrationStatus Status    `bson:"registration_status,omitempty"`
+	Error              string    `bson:"error,omitempty"`
+	ErrorCode          string    `bson:"error_code,omitempty"`
+	Note               string    `bson:"note,omitempty"`
+	DuplicateOf        int       `bson:"duplicate_of,omitempty"` // 1 based number of the first link of the channel
+	AnalyzedAt         time.Time `bson:"analyzed_at,omitempty"`
+}
+
+type Job struct {
+	ID             string      `json:"id" bson:"_id"`
+	UserID         string      `json:"user_id" bson:"user_id"` // ID of the user who uploaded the file
+	Status         JobStatus   `json:"status" bson:"status"`
+	InputFile      string      `json:"input_file" bson:"input_file"`
+	OutputFile     string      `json:"output_file" bson:"output_file"`
+	Options        JobOptions  `json:"options" bson:"options"`
+	TotalLinks     int         `json:"total_links" bson:"total_links"`
+	ProcessedLinks int         `json:"processed_links" bson:"processed_links"`
+	Results        [][]string  `json:"results,omitempty" bson:"results,omitempty"`         // Displayed rows, the header first
+	Records        []JobResult `json:"-" bson:"records,omitempty"`                         // Typed results the exports are written from
+	ArtifactID     string      `json:"artifact_id,omitempty" bson:"artifact_id,omitempty"` // Artifact of the output file once the job completed
+	Error          string      `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt      time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at" bson:"updated_at"`
+	StartedAt      time.Time   `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	FinishedAt     time.Time   `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+func NewJob(userID, inputFile, outputFile string, options JobOptions) *Job {
+	return &Job{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Status:     JobPending,
+		InputFile:  inputFile,
+		OutputFile: outputFile,
+		Options:    options,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// IsFinished reports whether the job reached a terminal status
+func (j *Job) IsFinished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed || j.Status == JobCancelled
+}
+
+// Progress returns the percentage of processed links
+func (j *Job) Progress() int {
+	if j.TotalLinks == 0 {
+		if j.Status == JobCompleted {
+			return 100
+		}
+		return 0
+	}
+	return j.ProcessedLinks * 100 / j.TotalLinks
+}
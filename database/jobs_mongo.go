@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	JobsCollectionName = "influencer-jobs"
+)
+
+type JobMongoRepository struct {
+	client *mongo.Client
+	config *config.Config
+}
+
+var _ JobRepository = (*JobMongoRepository)(nil)
+
+func NewJobMongoRepository(client *mongo.Client, config *config.Config) (*JobMongoRepository, error) {
+	if client == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	return &JobMongoRepository{
+		client: client,
+		config: config,
+	}, nil
+}
+
+func (repo *JobMongoRepository) collection() *mongo.Collection {
+	return repo.client.Database(repo.config.InfluencersDBName).Collection(JobsCollectionName)
+}
+
+// SaveJob implements JobRepository.
+func (repo *JobMongoRepository) SaveJob(job *Job) error {
+	ctx := context.Background()
+	_, err := repo.collection().InsertOne(ctx, job)
+	return err
+}
+
+// UpdateJob implements JobRepository.
+func (repo *JobMongoRepository) UpdateJob(job *Job) error {
+	ctx := context.Background()
+	job.UpdatedAt = time.Now()
+	filter := bson.M{"_id": job.ID}
+	_, err := repo.collection().ReplaceOne(ctx, filter, job)
+	return err
+}
+
+// UpdateJobProgress implements JobRepository.
+func (repo *JobMongoRepository) UpdateJobProgress(jobID string, processed int, total int) error {
+	ctx := context.Background()
+	filter := bson.M{"_id": jobID}
+	update := bson.M{"$set": bson.M{
+		"processed_links": processed,
+		"total_links":     total,
+		"updated_at":      time.Now(),
+	}}
+	_, err := repo.collection().UpdateOne(ctx, filter, update)
+	return err
+}
+
+// GetJobByID implements JobRepository.
+func (repo *JobMongoRepository) GetJobByID(jobID string) (*Job, error) {
+	ctx := context.Background()
+	result := repo.collection().FindOne(ctx, bson.M{"_id": jobID})
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var job Job
+	if err := result.Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJobsByUserID implements JobRepository.
+func (repo *JobMongoRepository) GetJobsByUserID(userID string, page int, limit int) (AllJobs, error) {
+	ctx := context.Background()
+	collection := repo.collection()
+
+	skip := (page - 1) * limit
+	filter := bson.M{"user_id": userID}
+	// The results are left out of the listing, they can be large and are fetched per job
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"results": 0})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return AllJobs{}, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Failed to close cursor: %v", err)
+		}
+	}()
+
+	jobs := make([]*Job, 0)
+	for cursor.Next(ctx) {
+		var job Job
+		if err := cursor.Decode(&job); err != nil {
+			return AllJobs{}, err
+		}
+		jobs = append(jobs, &job)
+	}
+
+	totalCount, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return AllJobs{}, err
+	}
+
+	allJobs := AllJobs{
+		TotalCount: totalCount,
+		Jobs:       jobs,
+	}
+	allJobs.Pagination.Page = int64(page)
+	allJobs.Pagination.Limit = int64(limit)
+	return allJobs, nil
+}
+
+// GetJobsByStatus implements JobRepository.
+func (repo *JobMongoRepository) GetJobsByStatus(statuses ...JobStatus) ([]*Job, error) {
+	ctx := context.Background()
+	filter := bson.M{"status": bson.M{"$in": statuses}}
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := repo.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Failed to close cursor: %v", err)
+		}
+	}()
+
+	var jobs []*Job
+	for cursor.Next(ctx) {
+		var job Job
+		if err := cursor.Decode(&job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, cursor.Err()
+}
@@ -10,6 +10,11 @@ const (
 	UserRole       Role = "user"
 )
 
+// IsAdmin reports whether the role can see the data of every user
+func (r Role) IsAdmin() bool {
+	return r == AdminRole || r == SuperAdminRole
+}
+
 type Subscription string
 
 const (
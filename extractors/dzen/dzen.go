@@ -0,0 +1,76 @@
+package dzen
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = time.Minute
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"dzen.ru", "zen.yandex.ru"},
+	CanonicalHost:     "dzen.ru",
+	Channel:           channelOf,
+	Cost:              10 * time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the ID or the short name of the channel, articles and videos don't name their channel
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) == 0 {
+		return ""
+	}
+	switch segments[0] {
+	case "id":
+		if len(segments) < 2 {
+			return ""
+		}
+		return "id/" + segments[1]
+	case "a", "b", "video", "shorts", "news", "media":
+		return ""
+	}
+	return strings.ToLower(segments[0])
+}
+
+// DzenExtractor is an implementation of StatisticExtractor for Dzen channels (dzen.ru and the former zen.yandex.ru)
+type DzenExtractor struct {
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
+}
+
+// NewDzenExtractor creates a new DzenExtractor instance, its script is run by runner
+func NewDzenExtractor(runner extractor.ScriptRunner) *DzenExtractor {
+	return &DzenExtractor{
+		name:    "dzen",
+		timeout: defaultTimeout,
+		runner:  runner,
+	}
+}
+
+// Name returns the name of this extractor
+func (de *DzenExtractor) Name() string {
+	return de.name
+}
+
+// Platform returns the description of the links handled by this extractor
+func (de *DzenExtractor) Platform() extractor.Platform {
+	return platform
+}
+
+// CanHandle returns true if this extractor can handle the given link
+func (de *DzenExtractor) CanHandle(link string) bool {
+	return platform.Matches(link)
+}
+
+// Extract extracts channel information from the given link using Puppeteer script
+func (de *DzenExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ExtractWithScript(ctx, de.runner, de.timeout, "scripts/dzen.js", link)
+}
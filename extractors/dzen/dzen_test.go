@@ -0,0 +1,78 @@
+package dzen
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// outputRunner answers every script with its output
+type outputRunner string
+
+var _ extractor.ScriptRunner = outputRunner("")
+
+func (or outputRunner) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	return []byte(or), nil
+}
+
+func TestChannelOf(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://dzen.ru/id/5ae586563dceb76be76eca19", "id/5ae586563dceb76be76eca19"},
+		{"https://dzen.ru/id/5ae586563dceb76be76eca19?tab=articles", "id/5ae586563dceb76be76eca19"},
+		{"https://dzen.ru/Tinkoff_Journal", "tinkoff_journal"},
+		{"https://dzen.ru/tinkoff_journal/", "tinkoff_journal"},
+		{"https://dzen.ru/id", ""},
+		{"https://dzen.ru/a/ZFJ3q1sN1Xs2Xx1y", ""},
+		{"https://dzen.ru/b/ZFJ3q1sN1Xs2Xx1y", ""},
+		{"https://dzen.ru/video/watch/64f1c2a5e8d3a1", ""},
+		{"https://dzen.ru/shorts/64f1c2a5e8d3a1", ""},
+		{"https://dzen.ru/news/story/123", ""},
+		{"https://dzen.ru/", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			u, err := url.Parse(tt.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := channelOf(u); got != tt.want {
+				t.Errorf("channelOf(%s) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFollowers(t *testing.T) {
+	// Counters as matched by scripts/dzen.js on the channel pages
+	tests := []struct {
+		followersText string
+		want          int64
+	}{
+		{"325 подписчиков", 325},
+		{"1 подписчик", 1},
+		{"12,5 тыс. подписчиков", 12500},
+		{"48 тыс. подписчиков", 48000},
+		{"1,2 млн подписчиков", 1200000},
+		{"2 млн подписчиков", 2000000},
+		{"1.2M subscribers", 1200000},
+		{"15K followers", 15000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.followersText, func(t *testing.T) {
+			output := `{"channelName":"Т—Ж","followersCount":"` + tt.followersText + `"}`
+			info, err := NewDzenExtractor(outputRunner(output)).Extract(context.Background(), "dzen.ru/tinkoff_journal")
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			if info.FollowersCount != tt.want || info.ChannelName != "Т—Ж" || info.OriginalLink != "https://dzen.ru/tinkoff_journal" {
+				t.Errorf("Extract = %+v, want %d followers", info, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package extractor
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound       = errors.New("channel not found")
+	ErrPrivateAccount = errors.New("private account")
+	ErrLoginRequired  = errors.New("login required")
+	ErrRateLimited    = errors.New("rate limited")
+	ErrScriptCrash    = errors.New("script crashed")
+	ErrParseFailure   = errors.New("failed to parse channel information")
+	ErrTimeout        = errors.New("extraction timed out")
+)
+
+// errorCodes maps the error codes printed by the scripts in their "error" field to our errors
+var errorCodes = map[string]error{
+	"not_found":      ErrNotFound,
+	"private":        ErrPrivateAccount,
+	"login_required": ErrLoginRequired,
+	"rate_limited":   ErrRateLimited,
+	"script_error":   ErrScriptCrash,
+	"parse_failure":  ErrParseFailure,
+	"timeout":        ErrTimeout,
+}
+
+// ScriptError converts the error code reported by a script into one of the extraction errors.
+// It returns nil when the script did not report an error.
+func ScriptError(code string) error {
+	if code == "" {
+		return nil
+	}
+	if err, ok := errorCodes[code]; ok {
+		return err
+	}
+	return fmt.Errorf("%w: unknown error code %q", ErrScriptCrash, code)
+}
+
+// ErrorCode returns the code of the extraction error wrapped by err, or "unknown"
+func ErrorCode(err error) string {
+	for code, target := range errorCodes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	return "unknown"
+}
@@ -1,13 +1,19 @@
 package extractor
 
+import "context"
+
 // ChannelInfo holds information about a channel
 type ChannelInfo struct {
 	ChannelName        string
-	FollowersCount     string
+	FollowersCount     int64
+	FollowersText      string // raw text scraped from the page, kept for auditing
+	VideosCount        int64  // 0 when the platform does not show it
 	OriginalLink       string
+	ChannelLink        string // link of the channel when the extractor resolved a post or video link
 	Platform           string
 	IsRegistered       bool
 	RegistrationStatus string
+	Attempts           int // Number of extraction attempts, set by RetryingExtractor
 }
 
 // StatisticExtractor defines the interface for extracting statistics from channels
@@ -15,8 +21,12 @@ type StatisticExtractor interface {
 	// CanHandle returns true if this extractor can handle the given link
 	CanHandle(link string) bool
 
-	// Extract extracts channel information from the given link
-	Extract(link string) ChannelInfo
+	// Platform describes the links handled by this extractor
+	Platform() Platform
+
+	// Extract extracts channel information from the given link, it stops when ctx is done.
+	// Failures are reported with one of the extraction errors (ErrNotFound, ErrTimeout, ...).
+	Extract(ctx context.Context, link string) (ChannelInfo, error)
 
 	// Name returns the name of this extractor
 	Name() string
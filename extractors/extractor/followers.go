@@ -0,0 +1,108 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// followersNumberRegex matches the first number of the text and the word right after it,
+// e.g. "1,5" and "тыс" in "1,5 тыс. подписчиков"
+var followersNumberRegex = regexp.MustCompile(`(\d(?:[\d.,' ]*\d)?)\s*([a-zа-яё]*)`)
+
+// followersMultipliers maps the abbreviations used by the platforms to their value.
+// Both the latin and the cyrillic "K" and "M" are used by the russian interfaces.
+var followersMultipliers = map[string]float64{
+	"k":        1e3,
+	"к":        1e3,
+	"т":        1e3,
+	"тыс":      1e3,
+	"thousand": 1e3,
+	"m":        1e6,
+	"м":        1e6,
+	"млн":      1e6,
+	"million":  1e6,
+	"b":        1e9,
+	"млрд":     1e9,
+	"billion":  1e9,
+}
+
+// followersMultiplierStems match the declined and plural forms of the multiplier words,
+// e.g. "тысяча", "тысячи", "тысяч" or "миллиона", "миллионов", "millions"
+var followersMultiplierStems = []struct {
+	stem       string
+	multiplier float64
+}{
+	{"тыс", 1e3},
+	{"thousand", 1e3},
+	{"миллион", 1e6},
+	{"million", 1e6},
+	{"миллиард", 1e9},
+	{"billion", 1e9},
+}
+
+// thousandsGroupRegex matches a number whose separators only group thousands, e.g. "12 345" or "1,234,567"
+var thousandsGroupRegex = regexp.MustCompile(`^\d{1,3}(?:[.,' ]\d{3})*$`)
+
+// followersMultiplier returns the multiplier of the word that follows the number
+func followersMultiplier(suffix string) (float64, bool) {
+	if multiplier, ok := followersMultipliers[suffix]; ok {
+		return multiplier, true
+	}
+	for _, s := range followersMultiplierStems {
+		if strings.HasPrefix(suffix, s.stem) {
+			return s.multiplier, true
+		}
+	}
+	return 0, false
+}
+
+// ParseFollowersCount converts the followers text scraped from a page into a number.
+// It understands thousands separators ("12 345", "3,700"), decimal commas ("1,5K"),
+// K/M/B suffixes and the russian words in every form ("12 тыс.", "1,2 млн", "10 тысяч", "1,5 миллиона")
+// and ignores the words around the number ("12 345 подписчиков", "1.2M Followers").
+// A decimal number without a multiplier ("1,5 подписчика") is ambiguous and fails.
+func ParseFollowersCount(text string) (int64, error) {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	// Non-breaking and thin spaces are used as thousands separators
+	normalized = strings.NewReplacer(
+		"\u00a0", " ",
+		"\u2007", " ",
+		"\u2009", " ",
+		"\u202f", " ",
+	).Replace(normalized)
+
+	match := followersNumberRegex.FindStringSubmatch(normalized)
+	if match == nil {
+		return 0, fmt.Errorf("%w: no number in followers text %q", ErrParseFailure, text)
+	}
+	number, suffix := match[1], match[2]
+
+	multiplier, ok := followersMultiplier(suffix)
+	if !ok {
+		// Without a multiplier every separator groups thousands
+		if strings.ContainsAny(number, ".,' ") && !thousandsGroupRegex.MatchString(number) {
+			return 0, fmt.Errorf("%w: decimal number without a multiplier in followers text %q", ErrParseFailure, text)
+		}
+		digits := strings.NewReplacer(" ", "", ",", "", ".", "", "'", "").Replace(number)
+		count, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid followers text %q: %v", ErrParseFailure, text, err)
+		}
+		return count, nil
+	}
+
+	// With a multiplier the last separator is the decimal one ("1,5K", "1.25M")
+	number = strings.NewReplacer(" ", "", "'", "").Replace(number)
+	if idx := strings.LastIndexAny(number, ".,"); idx != -1 {
+		integer := strings.NewReplacer(",", "", ".", "").Replace(number[:idx])
+		number = integer + "." + number[idx+1:]
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid followers text %q: %v", ErrParseFailure, text, err)
+	}
+	return int64(math.Round(value * multiplier)), nil
+}
@@ -0,0 +1,80 @@
+package extractor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFollowersCount(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int64
+	}{
+		// Texts as they are scraped from the pages of the platforms
+		{"telegram subscribers", "12 345 subscribers", 12345},
+		{"telegram members", "1 024 members", 1024},
+		{"telegram compact", "1.2K subscribers", 1200},
+		{"telegram non-breaking space", "98\u00a0765 subscribers", 98765},
+		{"vk subscribers", "12 345 подписчиков", 12345},
+		{"vk narrow no-break space", "1\u202f234\u202f567 подписчиков", 1234567},
+		{"vk thousands abbreviation", "12 тыс. подписчиков", 12000},
+		{"vk decimal thousands", "1,5K подписчиков", 1500},
+		{"youtube english", "1.2M subscribers", 1200000},
+		{"youtube russian millions", "1,2 млн подписчиков", 1200000},
+		{"youtube russian thousands", "45,6 тыс. подписчиков", 45600},
+		{"youtube billions", "1.1B subscribers", 1100000000},
+		{"instagram comma groups", "3,700 followers", 3700},
+		{"instagram compact", "12.5K Followers", 12500},
+		{"instagram millions", "285M followers", 285000000},
+		{"tiktok compact", "1.2M Followers", 1200000},
+		{"rutube thousands word", "10 тысяч подписчиков", 10000},
+		{"rutube thousand singular", "1 тысяча подписчиков", 1000},
+		{"rutube thousands plural", "2 тысячи подписчиков", 2000},
+		{"dzen millions word", "1,5 миллиона подписчиков", 1500000},
+		{"dzen million singular", "1 миллион подписчиков", 1000000},
+		{"dzen millions plural", "5 миллионов подписчиков", 5000000},
+		{"dzen billions word", "2 миллиарда подписчиков", 2000000000},
+		{"ok participants", "12 345 участников", 12345},
+		{"ok thousands", "3,4 тыс. участников", 3400},
+		{"english words", "2.5 million followers", 2500000},
+		{"cyrillic k", "15к подписчиков", 15000},
+		{"apostrophe groups", "1'234'567 followers", 1234567},
+		{"dot groups", "1.234.567 подписчиков", 1234567},
+		{"plain number", "42", 42},
+		{"text before the number", "Подписчики: 7 890", 7890},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFollowersCount(tt.text)
+			if err != nil {
+				t.Fatalf("ParseFollowersCount(%q) returned error: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFollowersCount(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFollowersCountErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"no number", "подписчики"},
+		{"decimal comma without multiplier", "1,5 подписчика"},
+		{"decimal dot without multiplier", "10.25 followers"},
+		{"unknown word after a decimal", "1,5 members"},
+		{"broken groups", "12 34 подписчиков"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFollowersCount(tt.text)
+			if !errors.Is(err, ErrParseFailure) {
+				t.Fatalf("ParseFollowersCount(%q) = %d, %v, want ErrParseFailure", tt.text, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package extractor
+
+import (
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultLinkCost is the processing time estimated for links of unknown platforms
+const defaultLinkCost = time.Second
+
+// Platform describes the links an extractor handles
+type Platform struct {
+	// Hosts of the links, their subdomains are included ("vk.com" matches "m.vk.com")
+	Hosts []string
+	// CanonicalHost replaces the host of the links when set, e.g. "telegram.me" becomes "t.me"
+	CanonicalHost string
+	// Normalize rewrites the link further once the scheme and host are canonical, it is optional
+	Normalize func(u *url.URL)
+	// Channel returns the handle or ID of the channel of a normalized link, post and video links
+	// resolve to the channel that owns them. It returns "" when the link does not name its channel.
+	Channel func(u *url.URL) string
+	// Cost is the estimated processing time of a link
+	Cost time.Duration
+	// RegistrationCheck reports whether the RKN registration check applies to the channels
+	RegistrationCheck bool
+}
+
+// Matches reports whether the link belongs to one of the hosts of the platform
+func (p Platform) Matches(link string) bool {
+	u, err := parseLink(link)
+	if err != nil {
+		return false
+	}
+	return p.matchesHost(strings.ToLower(u.Hostname()))
+}
+
+func (p Platform) matchesHost(host string) bool {
+	for _, h := range p.Hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelKey identifies a channel whatever the link used to reach it
+type ChannelKey struct {
+	Platform string // Name of the extractor of the channel
+	Channel  string // Handle or ID of the channel
+}
+
+// String returns the key in its stored form, "platform:channel"
+func (k ChannelKey) String() string {
+	return k.Platform + ":" + k.Channel
+}
+
+// PathSegments returns the non empty segments of the path of the link
+func PathSegments(u *url.URL) []string {
+	var segments []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Registry is the list of the extractors in use, every component recognizing links consults it
+type Registry struct {
+	extractors []StatisticExtractor
+}
+
+// NewRegistry creates a new Registry instance, the first extractor handling a link wins
+func NewRegistry(extractors ...StatisticExtractor) *Registry {
+	if len(extractors) == 0 {
+		log.Fatal("At least one extractor must be provided")
+	}
+	return &Registry{extractors: extractors}
+}
+
+// Lookup returns the extractor handling the link
+func (r *Registry) Lookup(link string) (StatisticExtractor, bool) {
+	u, err := parseLink(link)
+	if err != nil {
+		return nil, false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, e := range r.extractors {
+		if e.Platform().matchesHost(host) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// IsSupported reports whether an extractor handles the link
+func (r *Registry) IsSupported(link string) bool {
+	_, ok := r.Lookup(link)
+	return ok
+}
+
+// Normalize standardizes a link to use https and the canonical host of its platform
+func (r *Registry) Normalize(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := parseLink(link)
+	if err != nil {
+		log.Printf("Warning: Could not parse link '%s'. Using it as is. Error: %v", link, err)
+		return link
+	}
+	if e, ok := r.Lookup(link); ok {
+		normalize(e.Platform(), u)
+	} else {
+		u.Scheme = "https"
+	}
+	return u.String()
+}
+
+// ChannelKey resolves the link to the channel it belongs to.
+// It returns false for unsupported links and links that do not name their channel.
+func (r *Registry) ChannelKey(link string) (ChannelKey, bool) {
+	e, ok := r.Lookup(link)
+	if !ok || e.Platform().Channel == nil {
+		return ChannelKey{}, false
+	}
+	u, err := parseLink(strings.TrimSpace(link))
+	if err != nil {
+		return ChannelKey{}, false
+	}
+	normalize(e.Platform(), u)
+
+	channel := e.Platform().Channel(u)
+	if channel == "" {
+		return ChannelKey{}, false
+	}
+	return ChannelKey{Platform: e.Name(), Channel: channel}, true
+}
+
+// Cost returns the estimated processing time of the link
+func (r *Registry) Cost(link string) time.Duration {
+	if e, ok := r.Lookup(link); ok && e.Platform().Cost > 0 {
+		return e.Platform().Cost
+	}
+	return defaultLinkCost
+}
+
+// normalize rewrites the link to use https and the canonical host of the platform
+func normalize(platform Platform, u *url.URL) {
+	u.Scheme = "https"
+	if platform.CanonicalHost != "" {
+		u.Host = platform.CanonicalHost
+	}
+	if platform.Normalize != nil {
+		platform.Normalize(u)
+	}
+}
+
+// parseLink parses the link, links without a scheme are read as https links
+func parseLink(link string) (*url.URL, error) {
+	link = strings.TrimSpace(link)
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		link = "https://" + link
+	}
+	return url.Parse(link)
+}
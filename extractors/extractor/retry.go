@@ -0,0 +1,118 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// DefaultRetryPlatform is the key of the retry policy used by platforms without their own
+const DefaultRetryPlatform = "default"
+
+// RetryPolicy describes how many times a failed extraction is attempted
+// and how long to wait between the attempts.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts, 1 disables retries
+	BaseDelay   time.Duration // Delay before the second attempt, doubled for every following one
+	MaxDelay    time.Duration // Upper bound of the delay between two attempts
+	Jitter      float64       // Fraction of the delay randomly added or removed, from 0 to 1
+}
+
+// DefaultRetryPolicy is used when no policy is configured
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   2 * time.Second,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.2,
+}
+
+// RetryPolicies holds the retry policy of every platform
+type RetryPolicies map[string]RetryPolicy
+
+// For returns the policy of the platform, falling back to the default one
+func (p RetryPolicies) For(platform string) RetryPolicy {
+	if policy, ok := p[platform]; ok {
+		return policy
+	}
+	if policy, ok := p[DefaultRetryPlatform]; ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// IsTransient reports whether the error may go away when the extraction is attempted again.
+// Missing or private channels and cancellations are final.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrScriptCrash)
+}
+
+// Do calls fn until it succeeds, fails with a non transient error, the attempts run out
+// or ctx is done. It returns the number of attempts made and the last error.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil || !IsTransient(err) || attempt >= maxAttempts || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		delay := p.Backoff(attempt)
+		log.Printf("Attempt %d of %d failed: %v, retrying in %s", attempt, maxAttempts, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+	}
+}
+
+// Backoff returns the delay to wait after the given failed attempt
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// RetryingExtractor retries the transient failures of the extractor it wraps
+type RetryingExtractor struct {
+	StatisticExtractor
+	policy RetryPolicy
+}
+
+// WithRetry wraps the extractor so that its transient failures are retried following the policy
+func WithRetry(e StatisticExtractor, policy RetryPolicy) *RetryingExtractor {
+	return &RetryingExtractor{
+		StatisticExtractor: e,
+		policy:             policy,
+	}
+}
+
+// Extract extracts channel information from the given link, retrying transient failures.
+// The number of attempts made is recorded in the returned ChannelInfo.
+func (r *RetryingExtractor) Extract(ctx context.Context, link string) (ChannelInfo, error) {
+	var info ChannelInfo
+	attempts, err := r.policy.Do(ctx, func(ctx context.Context) error {
+		var err error
+		info, err = r.StatisticExtractor.Extract(ctx, link)
+		return err
+	})
+	info.Attempts = attempts
+	return info, err
+}
+
+var _ StatisticExtractor = (*RetryingExtractor)(nil)
@@ -0,0 +1,119 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// scriptWaitDelay bounds how long we wait for the output pipes once the script was killed
+const scriptWaitDelay = 5 * time.Second
+
+// ScriptRunner runs a scraping script and returns the JSON it printed
+type ScriptRunner interface {
+	Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error)
+}
+
+// ProcessRunner starts a new node process for every run, see RunScript
+type ProcessRunner struct{}
+
+// Run runs the script in a new node process
+func (ProcessRunner) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	return RunScript(ctx, timeout, script, args...)
+}
+
+var _ ScriptRunner = ProcessRunner{}
+
+// RunScript runs a Node.js script and returns its standard output.
+// The script and every process it started (e.g. the headless browser) are killed
+// when ctx is done or the timeout expires.
+// It returns ErrTimeout when the script ran out of time, ErrScriptCrash when it failed
+// and the context error as is when ctx was cancelled.
+func RunScript(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	parent := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "node", append([]string{script}, args...)...)
+	killProcessGroup(cmd)
+	cmd.WaitDelay = scriptWaitDelay
+
+	output, err := cmd.Output()
+	if parent.Err() != nil {
+		return output, parent.Err()
+	}
+	if ctx.Err() != nil {
+		return output, fmt.Errorf("%w: %s did not finish in %s", ErrTimeout, script, timeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return output, fmt.Errorf("%w: %v: %s", ErrScriptCrash, err, lastLine(exitErr.Stderr))
+		}
+		return output, fmt.Errorf("%w: %v", ErrScriptCrash, err)
+	}
+	return output, nil
+}
+
+// ExtractWithScript runs a scraping script printing the channelName and the followersCount of the channel
+// and returns the channel info. Links without a scheme are run as https.
+func ExtractWithScript(ctx context.Context, runner ScriptRunner, timeout time.Duration, script string, link string) (ChannelInfo, error) {
+	// Format the link to ensure it's accessible via http
+	if !strings.HasPrefix(link, "http") {
+		link = "https://" + link
+	}
+
+	output, err := runner.Run(ctx, timeout, script, link)
+	if err != nil {
+		log.Printf("Error executing %s for %s: %v", script, link, err)
+		return ChannelInfo{OriginalLink: link}, err
+	}
+
+	// Parse the JSON response from the script
+	var result struct {
+		ChannelName   string `json:"channelName"`
+		FollowersText string `json:"followersCount"`
+		Error         string `json:"error"`
+	}
+	if err := DecodeScriptOutput(output, &result); err != nil {
+		log.Printf("Error parsing %s output for %s: %v", script, link, err)
+		return ChannelInfo{OriginalLink: link}, err
+	}
+	if err := ScriptError(result.Error); err != nil {
+		return ChannelInfo{OriginalLink: link}, err
+	}
+	followersCount, err := ParseFollowersCount(result.FollowersText)
+	if err != nil {
+		return ChannelInfo{OriginalLink: link}, fmt.Errorf("followers of %q: %w", result.ChannelName, err)
+	}
+
+	return ChannelInfo{
+		ChannelName:    result.ChannelName,
+		FollowersCount: followersCount,
+		FollowersText:  result.FollowersText,
+		OriginalLink:   link,
+	}, nil
+}
+
+// DecodeScriptOutput parses the JSON printed by a script into v
+func DecodeScriptOutput(output []byte, v interface{}) error {
+	if err := json.Unmarshal(bytes.TrimSpace(output), v); err != nil {
+		return fmt.Errorf("%w: invalid script output %q: %v", ErrParseFailure, lastLine(output), err)
+	}
+	return nil
+}
+
+// lastLine returns the last non empty line of the output, which usually holds the error
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package extractor
+
+import "os/exec"
+
+// killProcessGroup is a no-op on platforms without process groups, only the script is killed.
+func killProcessGroup(cmd *exec.Cmd) {}
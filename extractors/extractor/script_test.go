@@ -0,0 +1,68 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRunner answers every script with its output and records the last run
+type fakeRunner struct {
+	output []byte
+	err    error
+	script string
+	args   []string
+}
+
+var _ ScriptRunner = (*fakeRunner)(nil)
+
+func (fr *fakeRunner) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	fr.script, fr.args = script, args
+	return fr.output, fr.err
+}
+
+func TestExtractWithScript(t *testing.T) {
+	runner := &fakeRunner{output: []byte(`{"channelName":"Durov","followersCount":"12,5 тыс. подписчиков"}` + "\n")}
+
+	info, err := ExtractWithScript(context.Background(), runner, time.Minute, "scripts/dzen.js", "dzen.ru/durov")
+	if err != nil {
+		t.Fatalf("ExtractWithScript: %v", err)
+	}
+	if runner.script != "scripts/dzen.js" || len(runner.args) != 1 || runner.args[0] != "https://dzen.ru/durov" {
+		t.Errorf("ran %s %q, want the script with the https link", runner.script, runner.args)
+	}
+	want := ChannelInfo{ChannelName: "Durov", FollowersCount: 12500, FollowersText: "12,5 тыс. подписчиков", OriginalLink: "https://dzen.ru/durov"}
+	if info != want {
+		t.Errorf("info = %+v, want %+v", info, want)
+	}
+}
+
+func TestExtractWithScriptErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr error
+	}{
+		{"runner timeout", "", ErrTimeout, ErrTimeout},
+		{"cancelled", "", context.Canceled, context.Canceled},
+		{"invalid output", "Error: net::ERR_NAME_NOT_RESOLVED", nil, ErrParseFailure},
+		{"script error code", `{"channelName":"Dzen Error","followersCount":"N/A","error":"not_found"}`, nil, ErrNotFound},
+		{"unknown error code", `{"channelName":"Dzen Error","followersCount":"N/A","error":"captcha"}`, nil, ErrScriptCrash},
+		{"followers without number", `{"channelName":"Durov","followersCount":"N/A"}`, nil, ErrParseFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{output: []byte(tt.output), err: tt.err}
+			info, err := ExtractWithScript(context.Background(), runner, time.Minute, "scripts/ok.js", "https://ok.ru/group/1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractWithScript error = %v, want %v", err, tt.wantErr)
+			}
+			// The link is kept so the failure can be reported
+			if info.OriginalLink != "https://ok.ru/group/1" {
+				t.Errorf("OriginalLink = %q, want the link", info.OriginalLink)
+			}
+		})
+	}
+}
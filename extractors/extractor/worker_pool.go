@@ -0,0 +1,319 @@
+package extractor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// workerPingTimeout bounds a health check, the first one launches the browser of the worker
+	workerPingTimeout = 30 * time.Second
+	// workerMaxLineSize is the size of the longest response line a worker may write
+	workerMaxLineSize = 1024 * 1024
+)
+
+// WorkerPoolOptions configures a WorkerPool
+type WorkerPoolOptions struct {
+	Command        []string      // Command starting a worker, e.g. node scripts/worker.js
+	Size           int           // Number of worker processes
+	HealthInterval time.Duration // Delay between two health checks of the idle workers, 0 disables them
+}
+
+// WorkerPool runs the scripts on long-lived worker processes instead of starting node for every link.
+// Workers read one JSON request per line on stdin and write one JSON response per line on stdout,
+// see scripts/worker.js. Dead, unhealthy or stuck workers are restarted.
+type WorkerPool struct {
+	command        []string
+	healthInterval time.Duration
+	size           int
+	idle           chan *scriptWorker // nil entries are workers that must be (re)started
+	ctx            context.Context    // Cancelled by Close, kills every worker
+	cancel         context.CancelFunc
+}
+
+// NewWorkerPool creates a new WorkerPool instance, its workers are started on first use
+func NewWorkerPool(opts WorkerPoolOptions) *WorkerPool {
+	if len(opts.Command) == 0 {
+		log.Fatal("worker command cannot be empty")
+	}
+	if opts.Size < 1 {
+		opts.Size = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &WorkerPool{
+		command:        opts.Command,
+		healthInterval: opts.HealthInterval,
+		size:           opts.Size,
+		idle:           make(chan *scriptWorker, opts.Size),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+	for i := 0; i < opts.Size; i++ {
+		p.idle <- nil
+	}
+	if p.healthInterval > 0 {
+		go p.checkHealth()
+	}
+	return p
+}
+
+// Run runs the script on an idle worker and returns the JSON result it produced.
+// The script is identified by its file name, "scripts/vk.js" runs the "vk" handler of the worker.
+// Errors follow RunScript: the context error as is, ErrTimeout and ErrScriptCrash.
+func (p *WorkerPool) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	w, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	name := strings.TrimSuffix(filepath.Base(script), filepath.Ext(script))
+	response, err := w.call(runCtx, workerRequest{Script: name, Args: args})
+	if err != nil {
+		// The worker may still be busy with the request, it is replaced by a new one
+		w.stop()
+		p.release(nil)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if runCtx.Err() != nil {
+			return nil, fmt.Errorf("%w: %s did not finish in %s", ErrTimeout, script, timeout)
+		}
+		return nil, fmt.Errorf("%w: %s: %v", ErrScriptCrash, script, err)
+	}
+	p.release(w)
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrScriptCrash, script, response.Error)
+	}
+	return response.Result, nil
+}
+
+// Close stops every worker, running scripts fail with ErrScriptCrash
+func (p *WorkerPool) Close() {
+	p.cancel()
+}
+
+func (p *WorkerPool) acquire(ctx context.Context) (*scriptWorker, error) {
+	select {
+	case w := <-p.idle:
+		if p.ctx.Err() != nil {
+			p.release(w)
+			return nil, fmt.Errorf("%w: worker pool closed", ErrScriptCrash)
+		}
+		if w != nil && w.alive() {
+			return w, nil
+		}
+		if w != nil {
+			log.Printf("Worker %d exited, restarting it", w.pid())
+		}
+		w, err := p.start()
+		if err != nil {
+			p.release(nil)
+			return nil, fmt.Errorf("%w: starting worker: %v", ErrScriptCrash, err)
+		}
+		return w, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.ctx.Done():
+		return nil, fmt.Errorf("%w: worker pool closed", ErrScriptCrash)
+	}
+}
+
+func (p *WorkerPool) release(w *scriptWorker) {
+	p.idle <- w
+}
+
+// checkHealth pings the idle workers periodically, the ones that don't answer are restarted
+func (p *WorkerPool) checkHealth() {
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.ctx.Done():
+			return
+		}
+
+		// Busy workers are skipped, they prove to be alive by answering their request
+		var workers []*scriptWorker
+	collect:
+		for len(workers) < p.size {
+			select {
+			case w := <-p.idle:
+				workers = append(workers, w)
+			default:
+				break collect
+			}
+		}
+
+		for _, w := range workers {
+			if w != nil {
+				ctx, cancel := context.WithTimeout(p.ctx, workerPingTimeout)
+				response, err := w.call(ctx, workerRequest{Type: "ping"})
+				cancel()
+				if err == nil && response.OK {
+					p.release(w)
+					continue
+				}
+				log.Printf("Worker %d failed its health check (%v %s), restarting it", w.pid(), err, response.Error)
+				w.stop()
+			}
+			if p.ctx.Err() != nil {
+				p.release(nil)
+				continue
+			}
+			started, err := p.start()
+			if err != nil {
+				log.Printf("Error starting worker: %v", err)
+			}
+			p.release(started)
+		}
+	}
+}
+
+func (p *WorkerPool) start() (*scriptWorker, error) {
+	ctx, cancel := context.WithCancel(p.ctx)
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	killProcessGroup(cmd)
+	cmd.WaitDelay = scriptWaitDelay
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	w := &scriptWorker{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan workerResponse, 1),
+		exited:    make(chan struct{}),
+		cancel:    cancel,
+	}
+	go w.read(ctx, stdout)
+	log.Printf("Started worker %d", w.pid())
+	return w, nil
+}
+
+type workerRequest struct {
+	ID     int      `json:"id"`
+	Type   string   `json:"type,omitempty"`
+	Script string   `json:"script,omitempty"`
+	Args   []string `json:"args,omitempty"`
+}
+
+type workerResponse struct {
+	ID     int             `json:"id"`
+	OK     bool            `json:"ok,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// scriptWorker is a worker process, it runs one request at a time
+type scriptWorker struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	nextID    int
+	responses chan workerResponse
+	exited    chan struct{} // Closed once the process exited
+	cancel    context.CancelFunc
+}
+
+// call sends the request and waits for its response
+func (w *scriptWorker) call(ctx context.Context, request workerRequest) (workerResponse, error) {
+	w.nextID++
+	request.ID = w.nextID
+	line, err := json.Marshal(request)
+	if err != nil {
+		return workerResponse{}, err
+	}
+	if _, err := w.stdin.Write(append(line, '\n')); err != nil {
+		return workerResponse{}, err
+	}
+
+	for {
+		select {
+		case response := <-w.responses:
+			if response.ID != request.ID {
+				continue // Stale answer to an earlier request
+			}
+			return response, nil
+		case <-w.exited:
+			return workerResponse{}, errors.New("worker exited")
+		case <-ctx.Done():
+			return workerResponse{}, ctx.Err()
+		}
+	}
+}
+
+// read forwards the responses written by the worker until it exits
+func (w *scriptWorker) read(ctx context.Context, stdout io.Reader) {
+	defer close(w.exited)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), workerMaxLineSize)
+	for scanner.Scan() {
+		var response workerResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			log.Printf("Invalid response from worker %d: %q", w.pid(), lastLine(scanner.Bytes()))
+			continue
+		}
+		select {
+		case w.responses <- response:
+		case <-ctx.Done():
+		}
+	}
+	if err := w.cmd.Wait(); err != nil && ctx.Err() == nil {
+		log.Printf("Worker %d exited: %v", w.pid(), err)
+	}
+}
+
+func (w *scriptWorker) alive() bool {
+	select {
+	case <-w.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop kills the worker and the browsers it started
+func (w *scriptWorker) stop() {
+	w.cancel()
+	_ = w.stdin.Close()
+}
+
+func (w *scriptWorker) pid() int {
+	return w.cmd.Process.Pid
+}
+
+var _ ScriptRunner = (*WorkerPool)(nil)
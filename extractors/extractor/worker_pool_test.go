@@ -0,0 +1,197 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeWorker is the path of cmd/fakeworker, built once for the tests of the pool
+var fakeWorker string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "fakeworker")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fakeWorker = filepath.Join(dir, "fakeworker")
+	build := exec.Command("go", "build", "-o", fakeWorker, "github.com/solrac97gr/telegram-followers-checker/cmd/fakeworker")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "building the fake worker: %v\n", err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newTestWorkerPool starts a pool of fake workers with the flags, it is closed with the test
+func newTestWorkerPool(t *testing.T, size int, healthInterval time.Duration, flags ...string) *WorkerPool {
+	t.Helper()
+	pool := NewWorkerPool(WorkerPoolOptions{
+		Command:        append([]string{fakeWorker}, flags...),
+		Size:           size,
+		HealthInterval: healthInterval,
+	})
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// idleWorker starts the next idle worker of the pool and puts it back
+func idleWorker(t *testing.T, pool *WorkerPool) *scriptWorker {
+	t.Helper()
+	w, err := pool.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	pool.release(w)
+	return w
+}
+
+// waitExited fails the test when the worker is still running after a few seconds
+func waitExited(t *testing.T, w *scriptWorker) {
+	t.Helper()
+	select {
+	case <-w.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("worker %d is still running", w.pid())
+	}
+}
+
+func TestWorkerPoolRun(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 0, "-registered")
+
+	output, err := pool.Run(context.Background(), time.Second, "scripts/telegram.js", "https://t.me/durov")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var result struct {
+		ChannelName    string `json:"channelName"`
+		FollowersCount string `json:"followersCount"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil || result.ChannelName != "Fake Channel" {
+		t.Fatalf("Run returned %s (%v), want the canned result", output, err)
+	}
+
+	// The registration checks run on the same long-lived worker
+	w := idleWorker(t, pool)
+	output, err = pool.Run(context.Background(), time.Second, "scripts/ru-registration.js", "https://t.me/durov")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if string(output) != `{"isRegistered":true}` {
+		t.Errorf("Run returned %s, want the registration result", output)
+	}
+	if again := idleWorker(t, pool); again != w || !again.alive() {
+		t.Errorf("the worker was replaced after a successful script")
+	}
+}
+
+func TestWorkerPoolRunCancelled(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 0, "-delay", "5s")
+	w := idleWorker(t, pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Run(ctx, time.Minute, "scripts/telegram.js"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run error = %v, want the context error", err)
+	}
+	waitExited(t, w)
+}
+
+func TestWorkerPoolTimeoutReplacesWorker(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 0, "-delay", "500ms")
+	stuck := idleWorker(t, pool)
+
+	_, err := pool.Run(context.Background(), 50*time.Millisecond, "scripts/telegram.js")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Run error = %v, want ErrTimeout", err)
+	}
+	// The worker may still be busy with the request, it is killed
+	waitExited(t, stuck)
+
+	if _, err := pool.Run(context.Background(), 5*time.Second, "scripts/telegram.js"); err != nil {
+		t.Fatalf("Run after the timeout: %v", err)
+	}
+	if w := idleWorker(t, pool); w == stuck || w.pid() == stuck.pid() {
+		t.Errorf("the timed out worker was reused")
+	}
+}
+
+func TestWorkerPoolRestartsCrashedWorker(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 0, "-crash-after", "1")
+
+	if _, err := pool.Run(context.Background(), 5*time.Second, "scripts/vk.js"); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	crashed := idleWorker(t, pool)
+	if _, err := pool.Run(context.Background(), 5*time.Second, "scripts/vk.js"); !errors.Is(err, ErrScriptCrash) {
+		t.Fatalf("Run on the crashing worker = %v, want ErrScriptCrash", err)
+	}
+	waitExited(t, crashed)
+
+	if _, err := pool.Run(context.Background(), 5*time.Second, "scripts/vk.js"); err != nil {
+		t.Fatalf("Run after the crash: %v", err)
+	}
+	if w := idleWorker(t, pool); w.pid() == crashed.pid() {
+		t.Errorf("the crashed worker was not restarted")
+	}
+}
+
+func TestWorkerPoolHealthCheckReplacesDeadWorker(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 50*time.Millisecond)
+	dead := idleWorker(t, pool)
+	if err := dead.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	waitExited(t, dead)
+
+	// The health check restarts the idle worker without any script being run
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w := <-pool.idle
+		pool.idle <- w
+		if w != nil && w != dead && w.alive() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("the dead worker was not replaced by the health check")
+}
+
+func TestWorkerPoolClose(t *testing.T) {
+	pool := newTestWorkerPool(t, 1, 0, "-delay", "5s")
+	busy := idleWorker(t, pool)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.Run(context.Background(), time.Minute, "scripts/telegram.js")
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	pool.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrScriptCrash) {
+			t.Fatalf("running script error = %v, want ErrScriptCrash", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the running script was not stopped by Close")
+	}
+	waitExited(t, busy)
+
+	if _, err := pool.Run(context.Background(), time.Second, "scripts/telegram.js"); !errors.Is(err, ErrScriptCrash) {
+		t.Fatalf("Run on a closed pool = %v, want ErrScriptCrash", err)
+	}
+}
@@ -1,11 +1,13 @@
 package instagram
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
+	"net/url"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
@@ -13,17 +15,52 @@ import (
 const (
 	InstagramUsernameEnv = "INSTAGRAM_USERNAME"
 	InstagramPasswordEnv = "INSTAGRAM_PASSWORD"
+
+	// defaultTimeout bounds a single run of the scraping script
+	defaultTimeout = 2 * time.Minute
 )
 
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:         []string{"instagram.com"},
+	CanonicalHost: "instagram.com",
+	Channel:       channelOf,
+	Cost:          time.Second,
+	// The RKN registration rule does not apply to Instagram
+	RegistrationCheck: false,
+}
+
+// channelOf returns the username of the account, posts and reels don't name their account
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) == 0 {
+		return ""
+	}
+	switch segments[0] {
+	case "p", "reel", "reels", "tv", "explore":
+		return ""
+	case "stories":
+		if len(segments) < 2 {
+			return ""
+		}
+		return strings.ToLower(segments[1])
+	}
+	return strings.ToLower(segments[0])
+}
+
 // InstagramExtractor is an implementation of StatisticExtractor for Instagram
 type InstagramExtractor struct {
-	name string
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
 }
 
-// NewInstagramExtractor creates a new InstagramExtractor instance
-func NewInstagramExtractor() *InstagramExtractor {
+// NewInstagramExtractor creates a new InstagramExtractor instance, its script is run by runner
+func NewInstagramExtractor(runner extractor.ScriptRunner) *InstagramExtractor {
 	return &InstagramExtractor{
-		name: "instagram",
+		name:    "instagram",
+		timeout: defaultTimeout,
+		runner:  runner,
 	}
 }
 
@@ -32,62 +69,54 @@ func (ie *InstagramExtractor) Name() string {
 	return ie.name
 }
 
+// Platform returns the description of the links handled by this extractor
+func (ie *InstagramExtractor) Platform() extractor.Platform {
+	return platform
+}
+
 // CanHandle returns true if this extractor can handle the given link
 func (ie *InstagramExtractor) CanHandle(link string) bool {
-	return strings.Contains(link, "instagram.com/")
+	return platform.Matches(link)
 }
 
 // Extract extracts channel information from the given link
-func (ie *InstagramExtractor) Extract(link string) extractor.ChannelInfo {
+func (ie *InstagramExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
 	// Run the Node.js script using Puppeteer
-	cmd := exec.Command(
-		"node",
+	output, err := ie.runner.Run(
+		ctx,
+		ie.timeout,
 		"scripts/instagram.js",
 		link,
 		os.Getenv(InstagramUsernameEnv),
 		os.Getenv(InstagramPasswordEnv),
 	)
-	output, err := cmd.Output()
 	if err != nil {
 		log.Printf("Error running Puppeteer script: %v", err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
 	}
 
 	// Parse the JSON output from the Node.js script
 	var result struct {
-		ChannelName    string `json:"channelName"`
-		FollowersCount string `json:"followersCount"`
+		ChannelName   string `json:"channelName"`
+		FollowersText string `json:"followersCount"`
+		Error         string `json:"error"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
+	if err := extractor.DecodeScriptOutput(output, &result); err != nil {
 		log.Printf("Error parsing JSON output: %v", err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	if err := extractor.ScriptError(result.Error); err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	followersCount, err := extractor.ParseFollowersCount(result.FollowersText)
+	if err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, fmt.Errorf("followers of %q: %w", result.ChannelName, err)
 	}
-
-	// Convert followers count to a number
-	result.FollowersCount = convertFollowersCount(result.FollowersCount)
 
 	return extractor.ChannelInfo{
 		ChannelName:    result.ChannelName,
-		FollowersCount: result.FollowersCount,
+		FollowersCount: followersCount,
+		FollowersText:  result.FollowersText,
 		OriginalLink:   link,
-	}
-}
-
-// convertFollowersCount converts followers count from a string like "10K" to a number string like "10000"
-func convertFollowersCount(followersText string) string {
-	followersText = strings.ToUpper(followersText)
-	if strings.Contains(followersText, "K") {
-		followersText = strings.ReplaceAll(followersText, "K", "000")
-	} else if strings.Contains(followersText, "M") {
-		followersText = strings.ReplaceAll(followersText, "M", "000000")
-	}
-	return followersText
+	}, nil
 }
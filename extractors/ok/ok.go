@@ -0,0 +1,76 @@
+package ok
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = time.Minute
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"ok.ru"},
+	CanonicalHost:     "ok.ru",
+	Channel:           channelOf,
+	Cost:              10 * time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the numeric ID or the short name of the group or profile, videos don't name their channel
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) == 0 {
+		return ""
+	}
+	switch segments[0] {
+	case "group", "profile":
+		if len(segments) < 2 {
+			return ""
+		}
+		return segments[0] + "/" + segments[1]
+	case "video", "live", "music", "feed":
+		return ""
+	}
+	return strings.ToLower(segments[0])
+}
+
+// OKExtractor is an implementation of StatisticExtractor for Odnoklassniki (ok.ru) groups and profiles
+type OKExtractor struct {
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
+}
+
+// NewOKExtractor creates a new OKExtractor instance, its script is run by runner
+func NewOKExtractor(runner extractor.ScriptRunner) *OKExtractor {
+	return &OKExtractor{
+		name:    "ok",
+		timeout: defaultTimeout,
+		runner:  runner,
+	}
+}
+
+// Name returns the name of this extractor
+func (oe *OKExtractor) Name() string {
+	return oe.name
+}
+
+// Platform returns the description of the links handled by this extractor
+func (oe *OKExtractor) Platform() extractor.Platform {
+	return platform
+}
+
+// CanHandle returns true if this extractor can handle the given link
+func (oe *OKExtractor) CanHandle(link string) bool {
+	return platform.Matches(link)
+}
+
+// Extract extracts channel information from the given link using Puppeteer script
+func (oe *OKExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ExtractWithScript(ctx, oe.runner, oe.timeout, "scripts/ok.js", link)
+}
@@ -0,0 +1,76 @@
+package ok
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// outputRunner answers every script with its output
+type outputRunner string
+
+var _ extractor.ScriptRunner = outputRunner("")
+
+func (or outputRunner) Run(ctx context.Context, timeout time.Duration, script string, args ...string) ([]byte, error) {
+	return []byte(or), nil
+}
+
+func TestChannelOf(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://ok.ru/group/53038939046008", "group/53038939046008"},
+		{"https://ok.ru/group/53038939046008/topic/155282513", "group/53038939046008"},
+		{"https://ok.ru/profile/577123456789", "profile/577123456789"},
+		{"https://ok.ru/RiaNovosti", "rianovosti"},
+		{"https://ok.ru/rianovosti/topic/155282513", "rianovosti"},
+		{"https://ok.ru/group", ""},
+		{"https://ok.ru/video/2307423668873", ""},
+		{"https://ok.ru/live/5567823", ""},
+		{"https://ok.ru/", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			u, err := url.Parse(tt.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := channelOf(u); got != tt.want {
+				t.Errorf("channelOf(%s) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFollowers(t *testing.T) {
+	// Counters as matched by scripts/ok.js on the group and profile pages
+	tests := []struct {
+		followersText string
+		want          int64
+	}{
+		{"12 345 участников", 12345},
+		{"987 участников", 987},
+		{"1 участник", 1},
+		{"3 456 789 участников", 3456789},
+		{"12,5 тыс. подписчиков", 12500},
+		{"1,2 млн участников", 1200000},
+		{"48 тыс. участников", 48000},
+		{"1.5K members", 1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.followersText, func(t *testing.T) {
+			output := `{"channelName":"РИА Новости","followersCount":"` + tt.followersText + `"}`
+			info, err := NewOKExtractor(outputRunner(output)).Extract(context.Background(), "ok.ru/rianovosti")
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			if info.FollowersCount != tt.want || info.ChannelName != "РИА Новости" || info.OriginalLink != "https://ok.ru/rianovosti" {
+				t.Errorf("Extract = %+v, want %d followers", info, tt.want)
+			}
+		})
+	}
+}
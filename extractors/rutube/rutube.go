@@ -1,23 +1,54 @@
 package rutube
 
 import (
-	"encoding/json"
-	"log"
-	"os/exec"
+	"context"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
 
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = time.Minute
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"rutube.ru"},
+	CanonicalHost:     "rutube.ru",
+	Channel:           channelOf,
+	Cost:              time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the ID of the channel, video links don't name their channel
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) < 2 {
+		return ""
+	}
+	switch segments[0] {
+	case "channel":
+		return segments[1]
+	case "u":
+		return "u/" + strings.ToLower(segments[1])
+	}
+	return ""
+}
+
 // RutubeExtractor is an implementation of StatisticExtractor for Rutube
 type RutubeExtractor struct {
-	name string
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
 }
 
-// NewRutubeExtractor creates a new RutubeExtractor instance
-func NewRutubeExtractor() *RutubeExtractor {
+// NewRutubeExtractor creates a new RutubeExtractor instance, its script is run by runner
+func NewRutubeExtractor(runner extractor.ScriptRunner) *RutubeExtractor {
 	return &RutubeExtractor{
-		name: "rutube",
+		name:    "rutube",
+		timeout: defaultTimeout,
+		runner:  runner,
 	}
 }
 
@@ -26,49 +57,17 @@ func (re *RutubeExtractor) Name() string {
 	return re.name
 }
 
+// Platform returns the description of the links handled by this extractor
+func (re *RutubeExtractor) Platform() extractor.Platform {
+	return platform
+}
+
 // CanHandle returns true if this extractor can handle the given link
 func (re *RutubeExtractor) CanHandle(link string) bool {
-	canHandle := strings.Contains(link, "rutube.ru/")
-	return canHandle
+	return platform.Matches(link)
 }
 
 // Extract extracts channel information from the given link using Puppeteer script
-func (re *RutubeExtractor) Extract(link string) extractor.ChannelInfo {
-	// Format the link to ensure it's accessible via http
-	if !strings.HasPrefix(link, "http") {
-		link = "https://" + link
-	}
-
-	// Execute the Rutube Puppeteer script
-	cmd := exec.Command("node", "scripts/rutube.js", link)
-	output, err := cmd.Output()
-	if err != nil {
-		log.Printf("Error executing Rutube script for %s: %v", link, err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
-	}
-
-	// Parse the JSON response from the script
-	var result struct {
-		ChannelName    string `json:"channelName"`
-		FollowersCount string `json:"followersCount"`
-	}
-
-	if err := json.Unmarshal(output, &result); err != nil {
-		log.Printf("Error parsing Rutube script output for %s: %v", link, err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
-	}
-
-	return extractor.ChannelInfo{
-		ChannelName:    result.ChannelName,
-		FollowersCount: result.FollowersCount,
-		OriginalLink:   link,
-	}
+func (re *RutubeExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ExtractWithScript(ctx, re.runner, re.timeout, "scripts/rutube.js", link)
 }
@@ -1,23 +1,61 @@
 package telegram
 
 import (
-	"encoding/json"
-	"log"
-	"os/exec"
+	"context"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
 
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = time.Minute
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"t.me", "telegram.me"},
+	CanonicalHost:     "t.me",
+	Channel:           channelOf,
+	Cost:              time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the username of the channel, "t.me/s/foo" and posts like "t.me/foo/123" belong to "foo"
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) > 0 && segments[0] == "s" {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	switch {
+	case segments[0] == "c" && len(segments) > 1:
+		// Private channels are only known by their numeric ID
+		return "c/" + segments[1]
+	case segments[0] == "joinchat" && len(segments) > 1:
+		// Invite hashes are case sensitive
+		return "+" + segments[1]
+	case strings.HasPrefix(segments[0], "+"):
+		return segments[0]
+	}
+	return strings.ToLower(segments[0])
+}
+
 // TelegramExtractor is an implementation of StatisticExtractor for Telegram
 type TelegramExtractor struct {
-	name string
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
 }
 
-// NewTelegramExtractor creates a new TelegramExtractor instance
-func NewTelegramExtractor() *TelegramExtractor {
+// NewTelegramExtractor creates a new TelegramExtractor instance, its script is run by runner
+func NewTelegramExtractor(runner extractor.ScriptRunner) *TelegramExtractor {
 	return &TelegramExtractor{
-		name: "telegram",
+		name:    "telegram",
+		timeout: defaultTimeout,
+		runner:  runner,
 	}
 }
 
@@ -26,48 +64,17 @@ func (te *TelegramExtractor) Name() string {
 	return te.name
 }
 
+// Platform returns the description of the links handled by this extractor
+func (te *TelegramExtractor) Platform() extractor.Platform {
+	return platform
+}
+
 // CanHandle returns true if this extractor can handle the given link
 func (te *TelegramExtractor) CanHandle(link string) bool {
-	return strings.Contains(link, "t.me/") || strings.Contains(link, "telegram.me/")
+	return platform.Matches(link)
 }
 
 // Extract extracts channel information from the given link using Puppeteer script
-func (te *TelegramExtractor) Extract(link string) extractor.ChannelInfo {
-	// Format the link to ensure it's accessible via http
-	if !strings.HasPrefix(link, "http") {
-		link = "https://" + link
-	}
-
-	// Execute the Telegram Puppeteer script
-	cmd := exec.Command("node", "scripts/telegram.js", link)
-	output, err := cmd.Output()
-	if err != nil {
-		log.Printf("Error executing Telegram script for %s: %v", link, err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
-	}
-
-	// Parse the JSON response from the script
-	var result struct {
-		ChannelName    string `json:"channelName"`
-		FollowersCount string `json:"followersCount"`
-	}
-
-	if err := json.Unmarshal(output, &result); err != nil {
-		log.Printf("Error parsing Telegram script output for %s: %v", link, err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
-	}
-
-	return extractor.ChannelInfo{
-		ChannelName:    result.ChannelName,
-		FollowersCount: result.FollowersCount,
-		OriginalLink:   link,
-	}
+func (te *TelegramExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ExtractWithScript(ctx, te.runner, te.timeout, "scripts/telegram.js", link)
 }
@@ -0,0 +1,155 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+const (
+	// defaultBaseURL is where the public preview pages of the channels are served
+	defaultBaseURL = "https://t.me"
+
+	userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+// errNoPreview is returned when the preview page has no usable followers counter
+var errNoPreview = errors.New("no followers counter in the preview page")
+
+// TelegramHTTPExtractor reads the static preview page of public channels without a browser.
+// Links whose preview can't be parsed are handed to the fallback extractor.
+type TelegramHTTPExtractor struct {
+	name     string
+	client   *http.Client
+	baseURL  string
+	fallback extractor.StatisticExtractor
+}
+
+// NewTelegramHTTPExtractor creates a new TelegramHTTPExtractor instance.
+// fallback, usually a TelegramExtractor, handles the pages that can't be parsed.
+func NewTelegramHTTPExtractor(client *http.Client, fallback extractor.StatisticExtractor) *TelegramHTTPExtractor {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &TelegramHTTPExtractor{
+		name:     "telegram",
+		client:   client,
+		baseURL:  defaultBaseURL,
+		fallback: fallback,
+	}
+}
+
+// Name returns the name of this extractor
+func (te *TelegramHTTPExtractor) Name() string {
+	return te.name
+}
+
+// Platform returns the description of the links handled by this extractor
+func (te *TelegramHTTPExtractor) Platform() extractor.Platform {
+	return platform
+}
+
+// CanHandle returns true if this extractor can handle the given link
+func (te *TelegramHTTPExtractor) CanHandle(link string) bool {
+	return platform.Matches(link)
+}
+
+// Extract extracts channel information from the preview page of the channel
+func (te *TelegramHTTPExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	// Format the link to ensure it's accessible via http
+	if !strings.HasPrefix(link, "http") {
+		link = "https://" + link
+	}
+
+	info, err := te.extractPreview(ctx, link)
+	if err == nil || ctx.Err() != nil || errors.Is(err, extractor.ErrNotFound) || errors.Is(err, extractor.ErrRateLimited) {
+		return info, err
+	}
+	if te.fallback == nil {
+		return info, fmt.Errorf("%w: %v", extractor.ErrParseFailure, err)
+	}
+
+	log.Printf("Telegram preview of %s can't be used (%v), falling back to %s", link, err, te.fallback.Name())
+	return te.fallback.Extract(ctx, link)
+}
+
+func (te *TelegramHTTPExtractor) extractPreview(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	info := extractor.ChannelInfo{OriginalLink: link}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return info, err
+	}
+	// Web views ("t.me/s/foo"), posts ("t.me/foo/123") and telegram.me links are read from
+	// the preview page of their channel
+	channel := channelOf(parsed)
+	if channel == "" {
+		return info, errNoPreview
+	}
+	previewURL := strings.TrimSuffix(te.baseURL, "/") + "/" + channel
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, previewURL, nil)
+	if err != nil {
+		return info, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := te.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return info, ctx.Err()
+		}
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return info, fmt.Errorf("%w: %s", extractor.ErrNotFound, link)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return info, fmt.Errorf("%w: %s", extractor.ErrRateLimited, link)
+	case resp.StatusCode != http.StatusOK:
+		return info, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return info, err
+	}
+	return parsePreview(doc, info)
+}
+
+// parsePreview reads the channel name and the followers counter of a t.me preview page
+func parsePreview(doc *goquery.Document, info extractor.ChannelInfo) (extractor.ChannelInfo, error) {
+	// Pages of unknown usernames have no title block
+	title := doc.Find(".tgme_page_title").First()
+	if title.Length() == 0 {
+		return info, fmt.Errorf("%w: %s", extractor.ErrNotFound, info.OriginalLink)
+	}
+	info.ChannelName = strings.TrimSpace(title.Text())
+
+	// Channels show "12 345 subscribers", groups "1 234 members, 56 online"
+	extra := strings.TrimSpace(doc.Find(".tgme_page_extra").First().Text())
+	lower := strings.ToLower(extra)
+	if !strings.Contains(lower, "subscriber") && !strings.Contains(lower, "member") && !strings.Contains(lower, "follower") {
+		return info, errNoPreview
+	}
+
+	followersCount, err := extractor.ParseFollowersCount(extra)
+	if err != nil {
+		return info, err
+	}
+	info.FollowersCount = followersCount
+	info.FollowersText = extra
+	return info, nil
+}
+
+var _ extractor.StatisticExtractor = (*TelegramHTTPExtractor)(nil)
@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// fallbackExtractor records the links handed to the fallback
+type fallbackExtractor struct {
+	mutex sync.Mutex
+	links []string
+}
+
+func (fe *fallbackExtractor) CanHandle(link string) bool   { return true }
+func (fe *fallbackExtractor) Platform() extractor.Platform { return platform }
+func (fe *fallbackExtractor) Name() string                 { return "fallback" }
+func (fe *fallbackExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+	fe.links = append(fe.links, link)
+	return extractor.ChannelInfo{OriginalLink: link, ChannelName: "from fallback"}, nil
+}
+
+// newPreviewServer serves the fixtures of testdata as t.me preview pages and records the requested paths
+func newPreviewServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	fixtures := map[string]string{
+		"/durov":               "channel.html",
+		"/gogroup":             "group.html",
+		"/someuser":            "user.html",
+		"/missing_channel_404": "not_found.html",
+	}
+	var mutex sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		paths = append(paths, r.URL.Path)
+		mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		page, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("reading fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	}))
+	t.Cleanup(server.Close)
+	return server, &paths
+}
+
+func newTestExtractor(server *httptest.Server, fallback extractor.StatisticExtractor) *TelegramHTTPExtractor {
+	te := NewTelegramHTTPExtractor(server.Client(), fallback)
+	te.baseURL = server.URL
+	return te
+}
+
+func TestTelegramHTTPExtractor(t *testing.T) {
+	tests := []struct {
+		name          string
+		link          string
+		wantPath      string
+		wantName      string
+		wantFollowers int64
+	}{
+		{"channel", "https://t.me/durov", "/durov", "Durov's Channel", 8871934},
+		{"link without scheme", "t.me/durov", "/durov", "Durov's Channel", 8871934},
+		{"web view", "https://t.me/s/durov", "/durov", "Durov's Channel", 8871934},
+		{"post", "https://t.me/durov/123", "/durov", "Durov's Channel", 8871934},
+		{"telegram.me host", "https://telegram.me/Durov", "/durov", "Durov's Channel", 8871934},
+		{"group members", "https://t.me/gogroup", "/gogroup", "Go Developers", 12345},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, paths := newPreviewServer(t)
+			fallback := &fallbackExtractor{}
+			te := newTestExtractor(server, fallback)
+
+			info, err := te.Extract(context.Background(), tt.link)
+			if err != nil {
+				t.Fatalf("Extract(%q) returned error: %v", tt.link, err)
+			}
+			if info.ChannelName != tt.wantName || info.FollowersCount != tt.wantFollowers {
+				t.Errorf("Extract(%q) = %q with %d followers, want %q with %d", tt.link, info.ChannelName, info.FollowersCount, tt.wantName, tt.wantFollowers)
+			}
+			if len(*paths) != 1 || (*paths)[0] != tt.wantPath {
+				t.Errorf("Extract(%q) requested %v, want [%s]", tt.link, *paths, tt.wantPath)
+			}
+			if len(fallback.links) != 0 {
+				t.Errorf("Extract(%q) used the fallback for %v", tt.link, fallback.links)
+			}
+		})
+	}
+}
+
+func TestTelegramHTTPExtractorErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		link         string
+		wantErr      error
+		wantFallback bool
+	}{
+		{"unknown username page", "https://t.me/missing_channel_404", extractor.ErrNotFound, false},
+		{"missing page", "https://t.me/gone", extractor.ErrNotFound, false},
+		{"rate limited", "https://t.me/limited", extractor.ErrRateLimited, false},
+		{"user without counter", "https://t.me/someuser", nil, true},
+		{"server error", "https://t.me/broken", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newPreviewServer(t)
+			fallback := &fallbackExtractor{}
+			te := newTestExtractor(server, fallback)
+
+			info, err := te.Extract(context.Background(), tt.link)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Extract(%q) error = %v, want %v", tt.link, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Extract(%q) returned error: %v", tt.link, err)
+			}
+			if got := len(fallback.links) == 1; got != tt.wantFallback {
+				t.Fatalf("Extract(%q) fallback calls = %v, want fallback %v", tt.link, fallback.links, tt.wantFallback)
+			}
+			if tt.wantFallback && info.ChannelName != "from fallback" {
+				t.Errorf("Extract(%q) = %+v, want the result of the fallback", tt.link, info)
+			}
+		})
+	}
+}
+
+func TestTelegramHTTPExtractorWithoutFallback(t *testing.T) {
+	server, _ := newPreviewServer(t)
+	te := newTestExtractor(server, nil)
+
+	if _, err := te.Extract(context.Background(), "https://t.me/someuser"); !errors.Is(err, extractor.ErrParseFailure) {
+		t.Fatalf("Extract without fallback error = %v, want ErrParseFailure", err)
+	}
+}
@@ -1,25 +1,51 @@
 package tiktok
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
-	"os/exec"
+	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
 
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = 90 * time.Second
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"tiktok.com"},
+	CanonicalHost:     "www.tiktok.com",
+	Channel:           channelOf,
+	Cost:              time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the username of the account, "tiktok.com/@foo/video/123" belongs to "foo"
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) == 0 || !strings.HasPrefix(segments[0], "@") {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(segments[0], "@"))
+}
+
 // TikTokExtractor is an implementation of StatisticExtractor for TikTok
 type TikTokExtractor struct {
-	name string
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
 }
 
-// NewTikTokExtractor creates a new TikTokExtractor instance
-func NewTikTokExtractor() *TikTokExtractor {
+// NewTikTokExtractor creates a new TikTokExtractor instance, its script is run by runner
+func NewTikTokExtractor(runner extractor.ScriptRunner) *TikTokExtractor {
 	return &TikTokExtractor{
-		name: "tiktok",
+		name:    "tiktok",
+		timeout: defaultTimeout,
+		runner:  runner,
 	}
 }
 
@@ -28,13 +54,18 @@ func (te *TikTokExtractor) Name() string {
 	return te.name
 }
 
+// Platform returns the description of the links handled by this extractor
+func (te *TikTokExtractor) Platform() extractor.Platform {
+	return platform
+}
+
 // CanHandle returns true if this extractor can handle the given link
 func (te *TikTokExtractor) CanHandle(link string) bool {
-	return strings.Contains(link, "tiktok.com/")
+	return platform.Matches(link)
 }
 
 // Extract extracts channel information from the given link
-func (te *TikTokExtractor) Extract(link string) extractor.ChannelInfo {
+func (te *TikTokExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
 	// Ensure link starts with https://
 	if !strings.HasPrefix(link, "http") {
 		link = "https://" + link
@@ -49,73 +80,35 @@ func (te *TikTokExtractor) Extract(link string) extractor.ChannelInfo {
 	println("Modified TikTok link:", modifiedLink)
 
 	// Run the Node.js script using Puppeteer
-	cmd := exec.Command("node", "scripts/tiktok.js", modifiedLink)
-	output, err := cmd.Output()
+	output, err := te.runner.Run(ctx, te.timeout, "scripts/tiktok.js", modifiedLink)
 	if err != nil {
 		log.Printf("Error running TikTok Puppeteer script: %v", err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
 	}
 
 	// Parse the JSON output from the Node.js script
 	var result struct {
-		ChannelName    string `json:"channelName"`
-		FollowersCount string `json:"followersCount"`
+		ChannelName   string `json:"channelName"`
+		FollowersText string `json:"followersCount"`
+		Error         string `json:"error"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
+	if err := extractor.DecodeScriptOutput(output, &result); err != nil {
 		log.Printf("Error parsing JSON output: %v", err)
 		log.Printf("Output: %s", string(output))
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	if err := extractor.ScriptError(result.Error); err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	followersCount, err := extractor.ParseFollowersCount(result.FollowersText)
+	if err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, fmt.Errorf("followers of %q: %w", result.ChannelName, err)
 	}
-
-	// Convert followers count to a number
-	result.FollowersCount = convertFollowersCount(result.FollowersCount)
 
 	return extractor.ChannelInfo{
 		ChannelName:    result.ChannelName,
-		FollowersCount: result.FollowersCount,
+		FollowersCount: followersCount,
+		FollowersText:  result.FollowersText,
 		OriginalLink:   link,
-	}
-}
-
-// convertFollowersCount converts followers count from formats like "20K", "1.5M", "5000" to a number string
-func convertFollowersCount(followersText string) string {
-	// Clean the input
-	followersText = strings.TrimSpace(followersText)
-	followersText = strings.ToUpper(followersText)
-
-	// Remove any non-alphanumeric characters except dots and common suffixes
-	re := regexp.MustCompile(`[^\d\.KM]`)
-	followersText = re.ReplaceAllString(followersText, "")
-
-	// Handle different formats
-	if strings.Contains(followersText, "K") {
-		// Handle formats like "20K" or "20.5K"
-		numStr := strings.ReplaceAll(followersText, "K", "")
-		if num, err := strconv.ParseFloat(numStr, 64); err == nil {
-			return strconv.Itoa(int(num * 1000))
-		}
-	} else if strings.Contains(followersText, "M") {
-		// Handle formats like "1M" or "1.5M"
-		numStr := strings.ReplaceAll(followersText, "M", "")
-		if num, err := strconv.ParseFloat(numStr, 64); err == nil {
-			return strconv.Itoa(int(num * 1000000))
-		}
-	} else {
-		// Handle plain numbers, remove any commas
-		followersText = strings.ReplaceAll(followersText, ",", "")
-		if num, err := strconv.Atoi(followersText); err == nil {
-			return strconv.Itoa(num)
-		}
-	}
-
-	// If conversion fails, return the original text
-	return followersText
+	}, nil
 }
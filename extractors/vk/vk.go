@@ -1,24 +1,67 @@
 package vk
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
-	"os/exec"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
 
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = 3 * time.Minute
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts:             []string{"vk.com"},
+	CanonicalHost:     "vk.com",
+	Channel:           channelOf,
+	Cost:              26 * time.Second,
+	RegistrationCheck: true,
+}
+
+// ownedPattern matches the links of the posts and videos of a community ("wall-123_456") or a user ("wall123_456")
+var ownedPattern = regexp.MustCompile(`^(?:wall|video|photo|clip)(-?)(\d+)_\d+$`)
+
+// communityPattern matches the numeric links of the communities, which are all known as "club123"
+var communityPattern = regexp.MustCompile(`^(?:club|public|event)(\d+)$`)
+
+// channelOf returns the screen name or the numeric ID of the page, posts and videos belong to their owner
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if len(segments) == 0 {
+		return ""
+	}
+	name := strings.ToLower(segments[0])
+	if match := ownedPattern.FindStringSubmatch(name); match != nil {
+		if match[1] == "-" {
+			return "club" + match[2]
+		}
+		return "id" + match[2]
+	}
+	if match := communityPattern.FindStringSubmatch(name); match != nil {
+		return "club" + match[1]
+	}
+	return name
+}
+
 // VKExtractor is an implementation of StatisticExtractor for VK
 type VKExtractor struct {
-	name string
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
 }
 
-// NewVKExtractor creates a new VKExtractor instance
-func NewVKExtractor() *VKExtractor {
+// NewVKExtractor creates a new VKExtractor instance, its script is run by runner
+func NewVKExtractor(runner extractor.ScriptRunner) *VKExtractor {
 	return &VKExtractor{
-		name: "vk",
+		name:    "vk",
+		timeout: defaultTimeout,
+		runner:  runner,
 	}
 }
 
@@ -27,13 +70,18 @@ func (ve *VKExtractor) Name() string {
 	return ve.name
 }
 
+// Platform returns the description of the links handled by this extractor
+func (ve *VKExtractor) Platform() extractor.Platform {
+	return platform
+}
+
 // CanHandle returns true if this extractor can handle the given link
 func (ve *VKExtractor) CanHandle(link string) bool {
-	return strings.Contains(link, "vk.com/")
+	return platform.Matches(link)
 }
 
 // Extract extracts channel information from the given link
-func (ve *VKExtractor) Extract(link string) extractor.ChannelInfo {
+func (ve *VKExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
 	// Ensure link starts with https://
 	if !strings.HasPrefix(link, "http") {
 		link = "https://" + link
@@ -48,50 +96,35 @@ func (ve *VKExtractor) Extract(link string) extractor.ChannelInfo {
 	println("Modified VK link:", modifiedLink)
 
 	// Run the Node.js script using Puppeteer
-	cmd := exec.Command("node", "scripts/vk.js", modifiedLink)
-	output, err := cmd.Output()
+	output, err := ve.runner.Run(ctx, ve.timeout, "scripts/vk.js", modifiedLink)
 	if err != nil {
 		log.Printf("Error running Puppeteer script: %v", err)
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
 	}
 
 	// Parse the JSON output from the Node.js script
 	var result struct {
-		ChannelName    string `json:"channelName"`
-		FollowersCount string `json:"followersText"`
+		ChannelName   string `json:"channelName"`
+		FollowersText string `json:"followersText"`
+		Error         string `json:"error"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
+	if err := extractor.DecodeScriptOutput(output, &result); err != nil {
 		log.Printf("Error parsing JSON output: %v", err)
 		log.Printf("Output: %s", string(output))
-		return extractor.ChannelInfo{
-			ChannelName:    "Error",
-			FollowersCount: "N/A",
-			OriginalLink:   link,
-		}
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	if err := extractor.ScriptError(result.Error); err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	followersCount, err := extractor.ParseFollowersCount(result.FollowersText)
+	if err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, fmt.Errorf("followers of %q: %w", result.ChannelName, err)
 	}
-
-	// Convert followers count to a number
-	result.FollowersCount = convertFollowersCount(result.FollowersCount)
 
 	return extractor.ChannelInfo{
 		ChannelName:    result.ChannelName,
-		FollowersCount: result.FollowersCount,
+		FollowersCount: followersCount,
+		FollowersText:  result.FollowersText,
 		OriginalLink:   link,
-	}
-}
-
-// convertFollowersCount converts followers count from a string like "10K" or "3,700" to a number string like "10000" or "3700"
-func convertFollowersCount(followersText string) string {
-	followersText = strings.ToUpper(followersText)
-	followersText = strings.ReplaceAll(followersText, ",", "")
-	if strings.Contains(followersText, "K") {
-		followersText = strings.ReplaceAll(followersText, "K", "000")
-	} else if strings.Contains(followersText, "M") {
-		followersText = strings.ReplaceAll(followersText, "M", "000000")
-	}
-	return followersText
+	}, nil
 }
@@ -0,0 +1,133 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// defaultTimeout bounds a single run of the scraping script
+const defaultTimeout = 90 * time.Second
+
+// platform describes the links handled by the extractor
+var platform = extractor.Platform{
+	Hosts: []string{"youtube.com", "youtu.be"},
+	// Video links keep the short youtu.be host, the channel is resolved by the script
+	Normalize: func(u *url.URL) {
+		if u.Hostname() != "youtu.be" {
+			u.Host = "www.youtube.com"
+		}
+	},
+	Channel:           channelOf,
+	Cost:              15 * time.Second,
+	RegistrationCheck: true,
+}
+
+// channelOf returns the handle or the ID of the channel.
+// Video links don't name their channel, the script resolves them.
+func channelOf(u *url.URL) string {
+	segments := extractor.PathSegments(u)
+	if u.Hostname() == "youtu.be" || len(segments) == 0 {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(segments[0], "@"):
+		return strings.ToLower(segments[0])
+	case segments[0] == "channel" && len(segments) > 1:
+		// Channel IDs are case sensitive
+		return segments[1]
+	case (segments[0] == "c" || segments[0] == "user") && len(segments) > 1:
+		return segments[0] + "/" + strings.ToLower(segments[1])
+	}
+	return ""
+}
+
+// YouTubeExtractor is an implementation of StatisticExtractor for YouTube.
+// It handles channel links (/@handle, /channel/ID, /c/name, /user/name) and video links
+// (youtu.be/ID, /watch?v=ID), which are resolved to the channel of their author.
+type YouTubeExtractor struct {
+	name    string
+	timeout time.Duration
+	runner  extractor.ScriptRunner
+}
+
+// NewYouTubeExtractor creates a new YouTubeExtractor instance, its script is run by runner
+func NewYouTubeExtractor(runner extractor.ScriptRunner) *YouTubeExtractor {
+	return &YouTubeExtractor{
+		name:    "youtube",
+		timeout: defaultTimeout,
+		runner:  runner,
+	}
+}
+
+// Name returns the name of this extractor
+func (ye *YouTubeExtractor) Name() string {
+	return ye.name
+}
+
+// Platform returns the description of the links handled by this extractor
+func (ye *YouTubeExtractor) Platform() extractor.Platform {
+	return platform
+}
+
+// CanHandle returns true if this extractor can handle the given link
+func (ye *YouTubeExtractor) CanHandle(link string) bool {
+	return platform.Matches(link)
+}
+
+// Extract extracts channel information from the given link using Puppeteer script
+func (ye *YouTubeExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	// Format the link to ensure it's accessible via http
+	if !strings.HasPrefix(link, "http") {
+		link = "https://" + link
+	}
+
+	output, err := ye.runner.Run(ctx, ye.timeout, "scripts/youtube.js", link)
+	if err != nil {
+		log.Printf("Error executing YouTube script for %s: %v", link, err)
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+
+	// Parse the JSON response from the script
+	var result struct {
+		ChannelName   string `json:"channelName"`
+		FollowersText string `json:"followersCount"`
+		VideosText    string `json:"videosCount"`
+		ChannelLink   string `json:"channelLink"`
+		Error         string `json:"error"`
+	}
+	if err := extractor.DecodeScriptOutput(output, &result); err != nil {
+		log.Printf("Error parsing YouTube script output for %s: %v", link, err)
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	if err := extractor.ScriptError(result.Error); err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	followersCount, err := extractor.ParseFollowersCount(result.FollowersText)
+	if err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, fmt.Errorf("followers of %q: %w", result.ChannelName, err)
+	}
+
+	// The videos counter is not shown on every channel, it is left to 0 when missing
+	var videosCount int64
+	if result.VideosText != "" {
+		if videosCount, err = extractor.ParseFollowersCount(result.VideosText); err != nil {
+			log.Printf("Ignoring videos count of %s: %v", link, err)
+			videosCount = 0
+		}
+	}
+
+	return extractor.ChannelInfo{
+		ChannelName:    result.ChannelName,
+		FollowersCount: followersCount,
+		FollowersText:  result.FollowersText,
+		VideosCount:    videosCount,
+		OriginalLink:   link,
+		ChannelLink:    result.ChannelLink,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package youtube
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+func TestChannelOf(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://www.youtube.com/@MrBeast", "@mrbeast"},
+		{"https://youtube.com/@mrbeast/videos", "@mrbeast"},
+		{"https://m.youtube.com/@MrBeast", "@mrbeast"},
+		{"https://www.youtube.com/channel/UCX6OQ3DkcsbYNE6H8uQQuVA", "UCX6OQ3DkcsbYNE6H8uQQuVA"},
+		{"https://www.youtube.com/channel/UCX6OQ3DkcsbYNE6H8uQQuVA/about", "UCX6OQ3DkcsbYNE6H8uQQuVA"},
+		{"https://www.youtube.com/c/MrBeast6000", "c/mrbeast6000"},
+		{"https://www.youtube.com/c/MrBeast6000/featured", "c/mrbeast6000"},
+		{"https://www.youtube.com/user/MrBeast6000", "user/mrbeast6000"},
+		{"https://www.youtube.com/channel", ""},
+		{"https://www.youtube.com/c", ""},
+		{"https://www.youtube.com/user", ""},
+		{"https://youtu.be/dQw4w9WgXcQ", ""},
+		{"https://youtu.be/@MrBeast", ""},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", ""},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", ""},
+		{"https://www.youtube.com/", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			u, err := url.Parse(tt.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := channelOf(u); got != tt.want {
+				t.Errorf("channelOf(%s) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	registry := extractor.NewRegistry(NewYouTubeExtractor(nil))
+	tests := []struct {
+		link string
+		want string
+	}{
+		// Video links keep their short host, the script resolves their channel
+		{"youtu.be/dQw4w9WgXcQ", "https://youtu.be/dQw4w9WgXcQ"},
+		{"http://youtu.be/dQw4w9WgXcQ?t=42", "https://youtu.be/dQw4w9WgXcQ?t=42"},
+		{"http://m.youtube.com/@MrBeast", "https://www.youtube.com/@MrBeast"},
+		{"youtube.com/watch?v=dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			if got := registry.Normalize(tt.link); got != tt.want {
+				t.Errorf("Normalize(%s) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelKey(t *testing.T) {
+	registry := extractor.NewRegistry(NewYouTubeExtractor(nil))
+	tests := []struct {
+		link   string
+		want   string
+		wantOK bool
+	}{
+		// Handles of any case and host are the same channel
+		{"youtube.com/@MrBeast", "youtube:@mrbeast", true},
+		{"https://m.youtube.com/@mrbeast/videos", "youtube:@mrbeast", true},
+		// Channel IDs differing by case are different channels
+		{"https://www.youtube.com/channel/UCX6OQ3DkcsbYNE6H8uQQuVA", "youtube:UCX6OQ3DkcsbYNE6H8uQQuVA", true},
+		{"https://www.youtube.com/channel/ucx6oq3dkcsbyne6h8uquva", "youtube:ucx6oq3dkcsbyne6h8uquva", true},
+		{"https://www.youtube.com/user/MrBeast6000", "youtube:user/mrbeast6000", true},
+		{"https://youtu.be/dQw4w9WgXcQ", "", false},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			key, ok := registry.ChannelKey(tt.link)
+			if ok != tt.wantOK || (ok && key.String() != tt.want) {
+				t.Errorf("ChannelKey(%s) = %q, %v, want %q, %v", tt.link, key, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
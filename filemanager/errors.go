@@ -0,0 +1,20 @@
+package filemanager
+
+import "errors"
+
+// Validation errors are caused by the uploaded file or the chosen options, the handlers report them
+// to the user. Other errors are internal failures.
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+	ErrInvalidFile       = errors.New("invalid file")
+	ErrInvalidOptions    = errors.New("invalid options")
+	ErrNoLinks           = errors.New("no valid links found")
+)
+
+// IsValidationError reports whether err is caused by the input file or the options
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrUnsupportedFormat) ||
+		errors.Is(err, ErrInvalidFile) ||
+		errors.Is(err, ErrInvalidOptions) ||
+		errors.Is(err, ErrNoLinks)
+}
@@ -1,176 +1,152 @@
 package filemanager
 
 import (
-	"encoding/csv"
+	"bytes"
+	"fmt"
 	"log"
-	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 	"github.com/xuri/excelize/v2"
 )
 
-// FileManager defines the interface for file operations
+// FileManager defines the interface for file operations.
+// Errors caused by the input file or the options wrap one of the validation errors (ErrNoLinks, ...).
 type FileManager interface {
-	ReadLinksFromExcel(filePath string) []string
-	ReadLinksFromFile(filePath string) []string // Generic method that detects file type
-	ReadLinksFromCSV(filePath string) []string
-	ReadLinksFromText(content string) []string
-	SaveResultsToExcel(data [][]string, outputPath string)
-	EstimateProcessingTime(filePath string) (int, error)
+	ReadLinksFromFile(filePath string, opts ReadOptions) ([]string, error) // Generic method that detects file type
+	ReadLinksFromText(content string) ([]string, error)
+	SaveResults(results []Result, format string, outputPath string) error // Writes the results with the ResultWriter of the format
+	ReadSheets(filePath string, opts ReadOptions) ([]*Sheet, error)       // Reads every row of the selected sheets for the enrich mode
+	SaveEnrichedExcel(sheets []*Sheet, data [][]string, outputPath string) error
+	EstimateProcessingTime(filePath string, opts ReadOptions) (int, error)
 }
 
 // FileManagerImpl implements the FileManager interface
-type FileManagerImpl struct{}
-
-// NewFileManager creates a new FileManager instance
-func NewFileManager() FileManager {
-	return &FileManagerImpl{}
+type FileManagerImpl struct {
+	registry *extractor.Registry
+	readers  []Reader
 }
 
-// normalizeLink standardizes a URL to use https and a canonical domain.
-func normalizeLink(link string) string {
-	link = strings.TrimSpace(link)
-	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
-		link = "https://" + link
+// NewFileManager creates a new FileManager instance.
+// Links are recognized and normalized with the platforms of the registry. Input files are read by the
+// first of the readers that detects them, DefaultReaders when none is given.
+func NewFileManager(registry *extractor.Registry, readers ...Reader) FileManager {
+	if registry == nil {
+		log.Fatal("registry cannot be nil")
 	}
-
-	u, err := url.Parse(link)
-	if err != nil {
-		log.Printf("Warning: Could not parse link '%s'. Using it as is. Error: %v", link, err)
-		return link
+	if len(readers) == 0 {
+		readers = DefaultReaders()
 	}
-
-	u.Scheme = "https"
-
-	host := strings.ToLower(u.Host)
-	if strings.HasSuffix(host, "vk.com") {
-		u.Host = "vk.com"
-	} else if strings.HasSuffix(host, "instagram.com") {
-		u.Host = "instagram.com"
-	} else if strings.HasSuffix(host, "rutube.ru") {
-		u.Host = "rutube.ru"
-	} else if strings.HasSuffix(host, "telegram.me") {
-		u.Host = "t.me"
-	} else if strings.HasSuffix(host, "t.me") {
-		u.Host = "t.me"
-	} else if strings.HasSuffix(host, "tiktok.com") {
-		u.Host = "www.tiktok.com"
-	}
-
-	return u.String()
+	return &FileManagerImpl{registry: registry, readers: readers}
 }
 
-// ReadLinksFromExcel reads links from an Excel file
-func (fm *FileManagerImpl) ReadLinksFromExcel(filePath string) []string {
-	// Open Excel file
-	f, err := excelize.OpenFile(filePath)
-	if err != nil {
-		log.Fatalf("Failed to open Excel file: %v", err)
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.Printf("Failed to close Excel file: %v", err)
+// ReadLinksFromFile reads the links of the file with the reader of its format
+func (fm *FileManagerImpl) ReadLinksFromFile(filePath string, opts ReadOptions) ([]string, error) {
+	// Check if it's a text file (for text input handling)
+	if strings.Contains(filePath, "_text_input.txt") {
+		// Read file content and treat as text input
+		content, err := readText(filePath)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	// Get all sheet names
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		log.Fatal("No sheets found in Excel file")
+		return fm.ReadLinksFromText(content)
 	}
 
-	// Get all rows in the first sheet
-	rows, err := f.GetRows(sheets[0])
+	sheets, err := fm.readFile(filePath, opts)
 	if err != nil {
-		log.Fatalf("Failed to get rows: %v", err)
+		return nil, err
 	}
-
-	links := make([]string, 0)
-
-	// Extract links from rows
-	for _, row := range rows {
-		for _, cell := range row {
-			if strings.Contains(cell, "t.me/") || strings.Contains(cell, "telegram.me/") || strings.Contains(cell, "rutube.ru/") || strings.Contains(cell, "vk.com/") || strings.Contains(cell, "instagram.com/") || strings.Contains(cell, "tiktok.com/") {
-				links = append(links, normalizeLink(cell))
-			}
-		}
-	}
-
+	links := linkValues(sheets)
 	if len(links) == 0 {
-		log.Fatal("No valid links found in the Excel file")
+		return nil, fmt.Errorf("%w in the file", ErrNoLinks)
 	}
 
-	return links
+	return links, nil
 }
 
-// ReadLinksFromFile is a generic method that detects file type and reads links accordingly
-func (fm *FileManagerImpl) ReadLinksFromFile(filePath string) []string {
-	ext := strings.ToLower(filepath.Ext(filePath))
+// readFile reads every row of the selected sheets of the file with the reader of its format and finds their links
+func (fm *FileManagerImpl) readFile(filePath string, opts ReadOptions) ([]*Sheet, error) {
+	reader, err := fm.readerFor(filePath)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := reader.Read(filePath)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if it's a text file (for text input handling)
-	if strings.Contains(filePath, "_text_input.txt") {
-		// Read file content and treat as text input
-		content, err := os.ReadFile(filePath)
+	// The sheets option is ignored for formats without sheets
+	if len(tables) == 1 && tables[0].Name == "" {
+		sheet, err := fm.newSheet(filePath, "", tables[0].Rows, opts)
 		if err != nil {
-			log.Fatalf("Failed to read text file: %v", err)
+			return nil, err
 		}
-		return fm.ReadLinksFromText(string(content))
+		return []*Sheet{sheet}, nil
 	}
 
-	switch ext {
-	case ".xlsx", ".xls":
-		return fm.ReadLinksFromExcel(filePath)
-	case ".csv":
-		return fm.ReadLinksFromCSV(filePath)
-	default:
-		log.Fatalf("Unsupported file format: %s. Supported formats: .xlsx, .xls, .csv", ext)
-		return nil
+	available := make([]string, 0, len(tables))
+	byName := make(map[string]Table, len(tables))
+	for _, table := range tables {
+		available = append(available, table.Name)
+		byName[table.Name] = table
 	}
-}
-
-// ReadLinksFromCSV reads links from a CSV file
-func (fm *FileManagerImpl) ReadLinksFromCSV(filePath string) []string {
-	file, err := os.Open(filePath)
+	names, err := selectSheets(available, opts)
 	if err != nil {
-		log.Fatalf("Failed to open CSV file: %v", err)
+		return nil, err
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Printf("Failed to close CSV file: %v", err)
+
+	sheets := make([]*Sheet, 0, len(names))
+	for _, name := range names {
+		sheet, err := fm.newSheet(filePath, name, byName[name].Rows, opts)
+		if err != nil && opts.AllSheets {
+			// Sheets without the column, like a summary tab, are skipped when reading every sheet
+			log.Printf("Skipping sheet %s: %v", name, err)
+			continue
 		}
-	}()
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", name, err)
+		}
+		// Only .xlsx workbooks are copied by the enriched output, the others are written in a new workbook
+		sheet.Workbook = byName[name].Workbook
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+// readText reads a text file, it must be encoded in UTF-8
+func readText(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		log.Fatalf("Failed to read CSV file: %v", err)
+		return "", err
 	}
-
-	links := make([]string, 0)
-
-	// Extract links from CSV records
-	for _, record := range records {
-		for _, cell := range record {
-			if strings.Contains(cell, "t.me/") || strings.Contains(cell, "telegram.me/") ||
-				strings.Contains(cell, "rutube.ru/") || strings.Contains(cell, "vk.com/") ||
-				strings.Contains(cell, "instagram.com/") || strings.Contains(cell, "youtube.com/") ||
-				strings.Contains(cell, "tiktok.com/") {
-				links = append(links, normalizeLink(cell))
-			}
-		}
+	// Spreadsheet programs start their UTF-8 exports with a byte order mark
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(content) {
+		return "", fmt.Errorf("%w: the file is not encoded in UTF-8, export it as \"CSV UTF-8\"", ErrInvalidFile)
 	}
+	return string(content), nil
+}
 
-	if len(links) == 0 {
-		log.Fatal("No valid links found in the CSV file")
+// newSheet finds the links of the rows in the column and after the header row selected by opts
+func (fm *FileManagerImpl) newSheet(filePath string, name string, rows [][]string, opts ReadOptions) (*Sheet, error) {
+	column, err := resolveColumn(rows, opts)
+	if err != nil {
+		return nil, err
 	}
-
-	return links
+	return &Sheet{
+		Path:      filePath,
+		Name:      name,
+		HeaderRow: opts.HeaderRow,
+		Rows:      rows,
+		Links:     fm.findLinks(rows, column, opts.HeaderRow),
+	}, nil
 }
 
 // ReadLinksFromText reads links from comma-separated text input
-func (fm *FileManagerImpl) ReadLinksFromText(content string) []string {
+func (fm *FileManagerImpl) ReadLinksFromText(content string) ([]string, error) {
 	// Split by various separators: comma, newline, space
 	content = strings.ReplaceAll(content, "\n", ",")
 	content = strings.ReplaceAll(content, " ", ",")
@@ -179,99 +155,196 @@ func (fm *FileManagerImpl) ReadLinksFromText(content string) []string {
 
 	for _, link := range rawLinks {
 		trimmed := strings.TrimSpace(link)
-		if trimmed != "" && (strings.Contains(trimmed, "t.me/") || strings.Contains(trimmed, "telegram.me/") ||
-			strings.Contains(trimmed, "rutube.ru/") || strings.Contains(trimmed, "vk.com/") ||
-			strings.Contains(trimmed, "instagram.com/") || strings.Contains(trimmed, "youtube.com/") ||
-			strings.Contains(trimmed, "tiktok.com/")) {
-			links = append(links, normalizeLink(trimmed))
+		if trimmed != "" && fm.registry.IsSupported(trimmed) {
+			links = append(links, fm.registry.Normalize(trimmed))
 		}
 	}
 
 	if len(links) == 0 {
-		log.Fatal("No valid links found in the text input")
+		return nil, fmt.Errorf("%w in the text input", ErrNoLinks)
+	}
+
+	return links, nil
+}
+
+// SaveResults saves the results of the links to the output file in the format, see NewResultWriter
+func (fm *FileManagerImpl) SaveResults(results []Result, format string, outputPath string) error {
+	writer, err := NewResultWriter(format)
+	if err != nil {
+		return err
 	}
+	return writer.Write(results, outputPath)
+}
+
+// ReadSheets reads every row of the selected sheets of the input file, their links are found the same way
+// as ReadLinksFromFile. Text input is a single sheet with one row per link.
+func (fm *FileManagerImpl) ReadSheets(filePath string, opts ReadOptions) ([]*Sheet, error) {
+	var sheets []*Sheet
 
-	return links
+	switch {
+	case strings.Contains(filePath, "_text_input.txt"):
+		content, err := readText(filePath)
+		if err != nil {
+			return nil, err
+		}
+		links, err := fm.ReadLinksFromText(content)
+		if err != nil {
+			return nil, err
+		}
+		sheet := &Sheet{Path: filePath}
+		for i, link := range links {
+			sheet.Rows = append(sheet.Rows, []string{link})
+			sheet.Links = append(sheet.Links, SheetLink{Row: i, Link: link})
+		}
+		sheets = []*Sheet{sheet}
+	default:
+		var err error
+		if sheets, err = fm.readFile(filePath, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(linkValues(sheets)) == 0 {
+		return nil, fmt.Errorf("%w in the file", ErrNoLinks)
+	}
+	return sheets, nil
 }
 
-// SaveResultsToExcel saves the extraction results to an Excel file
-func (fm *FileManagerImpl) SaveResultsToExcel(data [][]string, outputPath string) {
-	f := excelize.NewFile()
+// SaveEnrichedExcel saves the rows of the sheets with the results of their links appended after the last column.
+// data holds the header and then one result row per link, in the order of the links of the sheets.
+// Rows with several links get one block of result columns per link. Workbooks are copied so the
+// formatting and the other sheets are kept.
+func (fm *FileManagerImpl) SaveEnrichedExcel(sheets []*Sheet, data [][]string, outputPath string) error {
+	if len(sheets) == 0 || len(data) != len(linkValues(sheets))+1 {
+		return fmt.Errorf("got %d results for %d links", len(data)-1, len(linkValues(sheets)))
+	}
 
-	// Set column headers to be wider
-	_ = f.SetColWidth("Sheet1", "A", "A", 30)
-	_ = f.SetColWidth("Sheet1", "B", "B", 15)
-	_ = f.SetColWidth("Sheet1", "C", "C", 40)
+	var f *excelize.File
+	if sheets[0].Workbook {
+		var err error
+		if f, err = excelize.OpenFile(sheets[0].Path); err != nil {
+			return fmt.Errorf("failed to open Excel file: %w", err)
+		}
+	} else {
+		// CSV, text input and legacy workbooks are written as is in a new workbook, a sheet each
+		f = excelize.NewFile()
+		for i, sheet := range sheets {
+			name := enrichedSheetName(sheet)
+			if i == 0 {
+				_ = f.SetSheetName("Sheet1", name)
+			} else if _, err := f.NewSheet(name); err != nil {
+				return fmt.Errorf("failed to add sheet %s: %w", name, err)
+			}
+			for r, row := range sheet.Rows {
+				for c, cellValue := range row {
+					cellName, _ := excelize.CoordinatesToCellName(c+1, r+1)
+					_ = f.SetCellValue(name, cellName, cellValue)
+				}
+			}
+		}
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("Failed to close Excel file: %v", err)
+		}
+	}()
 
-	// Style for header
 	headerStyle, _ := f.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Bold: true,
-			Size: 12,
-		},
+		Font: &excelize.Font{Bold: true},
 		Fill: excelize.Fill{
 			Type:    "pattern",
 			Color:   []string{"#DDEBF7"},
 			Pattern: 1,
 		},
-		Alignment: &excelize.Alignment{
-			Horizontal: "center",
-			Vertical:   "center",
-		},
 	})
 
-	// Write data to Excel
-	for r, row := range data {
-		for c, cellValue := range row {
-			cellName, _ := excelize.CoordinatesToCellName(c+1, r+1)
-			_ = f.SetCellValue("Sheet1", cellName, cellValue)
+	next := 1
+	for _, sheet := range sheets {
+		if err := enrichSheet(f, enrichedSheetName(sheet), sheet, data[0], data[next:next+len(sheet.Links)], headerStyle); err != nil {
+			return err
 		}
+		next += len(sheet.Links)
+	}
+
+	if err := f.SaveAs(outputPath); err != nil {
+		return fmt.Errorf("failed to save Excel file: %w", err)
+	}
+	return nil
+}
+
+// enrichedSheetName is the name of the sheet in the enriched output, CSV and text input have no name
+func enrichedSheetName(sheet *Sheet) string {
+	if sheet.Name == "" {
+		return "Sheet1"
+	}
+	return sheet.Name
+}
+
+// enrichSheet writes the results of the links of the sheet after its widest row
+func enrichSheet(f *excelize.File, name string, sheet *Sheet, header []string, results [][]string, headerStyle int) error {
+	width := 0
+	for _, row := range sheet.Rows {
+		width = max(width, len(row))
+	}
+	// The results are grouped by row in the order of the links
+	blocks := make(map[int][][]string)
+	maxBlocks := 0
+	for i, link := range sheet.Links {
+		blocks[link.Row] = append(blocks[link.Row], results[i])
+		maxBlocks = max(maxBlocks, len(blocks[link.Row]))
+	}
+	if maxBlocks == 0 {
+		return nil
+	}
 
-		// Apply header style to first row
-		if r == 0 {
-			_ = f.SetRowHeight("Sheet1", 1, 20)
-			for c := range row {
-				cellName, _ := excelize.CoordinatesToCellName(c+1, r+1)
-				_ = f.SetCellStyle("Sheet1", cellName, cellName, headerStyle)
+	// The headers of the results go in the header row, without one they go in the first row.
+	// A row is inserted above the first row when it has links.
+	headerRow, offset := sheet.HeaderRow, 0
+	if headerRow == 0 {
+		headerRow = 1
+		if _, ok := blocks[0]; ok {
+			if err := f.InsertRows(name, 1, 1); err != nil {
+				return fmt.Errorf("failed to insert the header row of sheet %s: %w", name, err)
 			}
+			offset = 1
 		}
 	}
 
-	// Save the Excel file
-	if err := f.SaveAs(outputPath); err != nil {
-		log.Fatalf("Failed to save Excel file: %v", err)
+	for b := 0; b < maxBlocks; b++ {
+		for c, title := range header {
+			if b > 0 {
+				title = fmt.Sprintf("%s (%d)", title, b+1)
+			}
+			cellName, _ := excelize.CoordinatesToCellName(width+b*len(header)+c+1, headerRow)
+			_ = f.SetCellValue(name, cellName, title)
+			_ = f.SetCellStyle(name, cellName, cellName, headerStyle)
+		}
 	}
-}
 
-var (
-	estimationPerLink = map[string]int{
-		"vk": 26, // seconds
-		"tg": 1,  // seconds
-		"ig": 1,  // seconds
-		"rt": 1,  // seconds
+	for r, rowResults := range blocks {
+		for b, result := range rowResults {
+			for c, cellValue := range result {
+				cellName, _ := excelize.CoordinatesToCellName(width+b*len(header)+c+1, r+offset+1)
+				_ = f.SetCellValue(name, cellName, cellValue)
+			}
+		}
 	}
-)
+	return nil
+}
 
 // EstimateProcessingTime estimates the processing time based on the number of links
-func (fm *FileManagerImpl) EstimateProcessingTime(filePath string) (int, error) {
+func (fm *FileManagerImpl) EstimateProcessingTime(filePath string, opts ReadOptions) (int, error) {
 	// Use the universal file reader to get links count
-	links := fm.ReadLinksFromFile(filePath)
+	links, err := fm.ReadLinksFromFile(filePath, opts)
+	if err != nil {
+		return 0, err
+	}
 
-	// Estimate based on platform type
-	estimatedTime := 0
+	// Estimate based on the cost of the platform of every link
+	var estimatedTime time.Duration
 	for _, link := range links {
-		if strings.Contains(link, "vk.com/") {
-			estimatedTime += estimationPerLink["vk"]
-		} else if strings.Contains(link, "t.me/") || strings.Contains(link, "telegram.me/") {
-			estimatedTime += estimationPerLink["tg"]
-		} else if strings.Contains(link, "instagram.com/") {
-			estimatedTime += estimationPerLink["ig"]
-		} else if strings.Contains(link, "rutube.ru/") {
-			estimatedTime += estimationPerLink["rt"]
-		} else {
-			estimatedTime += 1 // Default estimation for unknown links
-		}
+		estimatedTime += fm.registry.Cost(link)
 	}
 
-	return estimatedTime, nil
+	return int(estimatedTime.Seconds()), nil
 }
@@ -0,0 +1,137 @@
+package filemanager
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+)
+
+// testExtractor recognizes the links of its hosts, it never extracts anything
+type testExtractor struct {
+	name  string
+	hosts []string
+}
+
+var _ extractor.StatisticExtractor = (*testExtractor)(nil)
+
+func (te *testExtractor) CanHandle(link string) bool {
+	return te.Platform().Matches(link)
+}
+
+func (te *testExtractor) Platform() extractor.Platform {
+	return extractor.Platform{Hosts: te.hosts}
+}
+
+func (te *testExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	return extractor.ChannelInfo{}, extractor.ErrNotFound
+}
+
+func (te *testExtractor) Name() string {
+	return te.name
+}
+
+// newTestFileManager creates a FileManager recognizing the Telegram and VK links
+func newTestFileManager() FileManager {
+	registry := extractor.NewRegistry(
+		&testExtractor{name: "telegram", hosts: []string{"t.me", "telegram.me"}},
+		&testExtractor{name: "vk", hosts: []string{"vk.com"}},
+	)
+	return NewFileManager(registry)
+}
+
+func testdata(name string) string {
+	return filepath.Join("testdata", name)
+}
+
+func TestReadLinksFromFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		opts ReadOptions
+		want []string
+	}{
+		{"csv", "links.csv", ReadOptions{}, []string{"https://t.me/durov", "https://vk.com/team"}},
+		{"csv column by name", "links.csv", ReadOptions{Column: "link", HeaderRow: 1}, []string{"https://t.me/durov", "https://vk.com/team"}},
+		{"xlsx", "links.xlsx", ReadOptions{Column: "Link", HeaderRow: 1}, []string{"https://t.me/durov", "https://vk.com/team", "https://telegram.me/durov"}},
+		{"xlsx renamed csv", "links_xlsx.csv", ReadOptions{Column: "B", HeaderRow: 1}, []string{"https://t.me/durov", "https://vk.com/team", "https://telegram.me/durov"}},
+		{"xlsx every sheet", "links.xlsx", ReadOptions{AllSheets: true}, []string{"https://t.me/durov", "https://vk.com/team", "https://telegram.me/durov"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := newTestFileManager().ReadLinksFromFile(testdata(tt.file), tt.opts)
+			if err != nil {
+				t.Fatalf("ReadLinksFromFile: %v", err)
+			}
+			if !slices.Equal(links, tt.want) {
+				t.Errorf("links = %q, want %q", links, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLinksFromFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		opts    ReadOptions
+		wantErr error
+	}{
+		{"empty csv", "empty.csv", ReadOptions{}, ErrNoLinks},
+		{"csv without links", "no_links.csv", ReadOptions{}, ErrNoLinks},
+		{"malformed csv", "malformed.csv", ReadOptions{}, ErrInvalidFile},
+		{"windows-1251 csv", "cp1251.csv", ReadOptions{}, ErrInvalidFile},
+		{"truncated xlsx", "truncated.xlsx", ReadOptions{}, ErrInvalidFile},
+		{"zip renamed csv", "archive.csv", ReadOptions{}, ErrInvalidFile},
+		{"html named xls", "report.xls", ReadOptions{}, ErrUnsupportedFormat},
+		{"text named xlsx", "not_a_workbook.xlsx", ReadOptions{}, ErrInvalidFile},
+		{"pdf", "links.pdf", ReadOptions{}, ErrUnsupportedFormat},
+		{"docx", "links.docx", ReadOptions{}, ErrUnsupportedFormat},
+		{"unknown sheet", "links.xlsx", ReadOptions{Sheets: []string{"Links"}}, ErrInvalidOptions},
+		{"unknown column", "links.xlsx", ReadOptions{Column: "Channel URL", HeaderRow: 1}, ErrInvalidOptions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := newTestFileManager()
+			_, err := fm.ReadLinksFromFile(testdata(tt.file), tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadLinksFromFile error = %v, want %v", err, tt.wantErr)
+			}
+			if !IsValidationError(err) {
+				t.Errorf("IsValidationError(%v) = false, want true", err)
+			}
+			// The enrich mode reads the sheets the same way, a file without links is still enriched
+			if tt.wantErr == ErrNoLinks {
+				return
+			}
+			if _, err := fm.ReadSheets(testdata(tt.file), tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadSheets error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unsupported format", ErrUnsupportedFormat, true},
+		{"wrapped invalid file", errors.Join(errors.New("sheet Links"), ErrInvalidFile), true},
+		{"invalid options", ErrInvalidOptions, true},
+		{"no links", ErrNoLinks, true},
+		{"internal error", errors.New("disk full"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidationError(tt.err); got != tt.want {
+				t.Errorf("IsValidationError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
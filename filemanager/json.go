@@ -0,0 +1,118 @@
+package filemanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// jsonReader reads JSON arrays of links or of objects with a link field
+type jsonReader struct{}
+
+var _ Reader = (*jsonReader)(nil)
+
+// Extensions returns the extension of JSON files
+func (jr *jsonReader) Extensions() []string {
+	return []string{".json"}
+}
+
+// Detect detects JSON files by extension
+func (jr *jsonReader) Detect(ext string, head []byte) bool {
+	return ext == ".json" && !isBinary(head) && !isMarkup(head)
+}
+
+// Read reads every item of the array as a row with a single cell
+func (jr *jsonReader) Read(filePath string) ([]Table, error) {
+	content, err := readText(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(content), "[") {
+		return nil, fmt.Errorf("%w: the file must hold a JSON array of links", ErrInvalidFile)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(content), &items); err != nil {
+		return nil, fmt.Errorf("%w: the file must hold a JSON array of links: %v", ErrInvalidFile, err)
+	}
+
+	rows := make([][]string, 0, len(items))
+	for i, item := range items {
+		link, err := jsonLink(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d: %v", ErrInvalidFile, i+1, err)
+		}
+		rows = append(rows, []string{link})
+	}
+	return []Table{{Rows: rows}}, nil
+}
+
+// jsonLinesReader reads JSON Lines files, one link or object with a link field per line
+type jsonLinesReader struct{}
+
+var _ Reader = (*jsonLinesReader)(nil)
+
+// Extensions returns the extensions of JSON Lines files
+func (jr *jsonLinesReader) Extensions() []string {
+	return []string{".jsonl", ".ndjson"}
+}
+
+// Detect detects JSON Lines files by extension
+func (jr *jsonLinesReader) Detect(ext string, head []byte) bool {
+	return (ext == ".jsonl" || ext == ".ndjson") && !isBinary(head) && !isMarkup(head)
+}
+
+// Read reads every line as a row with a single cell, empty lines are kept so row numbers match the file
+func (jr *jsonLinesReader) Read(filePath string) ([]Table, error) {
+	content, err := readText(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\r\n"), "\n")
+	rows := make([][]string, 0, len(lines))
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			rows = append(rows, []string{""})
+			continue
+		}
+		link, err := jsonLink([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, i+1, err)
+		}
+		rows = append(rows, []string{link})
+	}
+	return []Table{{Rows: rows}}, nil
+}
+
+// errNotALink is returned for JSON items that are neither a link nor an object with a link field
+var errNotALink = errors.New(`expected a link or an object with a "link" field`)
+
+// jsonLink reads a link, or the link field of an object
+func jsonLink(item []byte) (string, error) {
+	item = bytes.TrimSpace(item)
+	if len(item) > 0 && item[0] == '"' {
+		var link string
+		if err := json.Unmarshal(item, &link); err != nil {
+			return "", err
+		}
+		return link, nil
+	}
+	if len(item) == 0 || item[0] != '{' {
+		return "", errNotALink
+	}
+
+	var object struct {
+		Link *string `json:"link"`
+	}
+	if err := json.Unmarshal(item, &object); err != nil {
+		return "", err
+	}
+	if object.Link == nil {
+		return "", errNotALink
+	}
+	return *object.Link, nil
+}
@@ -0,0 +1,200 @@
+package filemanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// odsMimeType is stored uncompressed at the start of OpenDocument spreadsheets
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+// odsMaxRepeat caps the repeated rows and cells of a table, LibreOffice repeats the empty ones up to the
+// last row and column of the sheet
+const odsMaxRepeat = 1 << 16
+
+// odsReader reads OpenDocument spreadsheets saved by LibreOffice or exported from Google Sheets
+type odsReader struct{}
+
+var _ Reader = (*odsReader)(nil)
+
+// Extensions returns the extension of OpenDocument spreadsheets
+func (od *odsReader) Extensions() []string {
+	return []string{".ods"}
+}
+
+// Detect detects the zip archives that start with the OpenDocument spreadsheet mime type
+func (od *odsReader) Detect(ext string, head []byte) bool {
+	return bytes.HasPrefix(head, zipSignature) && bytes.Contains(head, []byte(odsMimeType))
+}
+
+// Read reads the text of the cells of every table of content.xml
+func (od *odsReader) Read(filePath string) ([]Table, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't open the OpenDocument file: %v", ErrInvalidFile, err)
+	}
+	defer archive.Close()
+
+	content, err := archive.Open("content.xml")
+	if err != nil {
+		return nil, fmt.Errorf("%w: the OpenDocument file has no content: %v", ErrInvalidFile, err)
+	}
+	defer content.Close()
+
+	tables, err := parseODSContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed OpenDocument content: %v", ErrInvalidFile, err)
+	}
+	return tables, nil
+}
+
+// parseODSContent reads the tables of content.xml.
+// Empty rows and cells are only added when a value follows them, like excelize GetRows.
+func parseODSContent(r io.Reader) ([]Table, error) {
+	decoder := xml.NewDecoder(r)
+	var tables []Table
+	var table *Table
+	var row []string
+	var cell *strings.Builder
+	var rowRepeat, cellRepeat, emptyRows, emptyCells int
+	// paragraph is the depth of the paragraphs of the cell, annotation the depth of its comments
+	var paragraph, annotation int
+	// href is the target of the first web link of the cell, its label is often the name of the channel
+	var href string
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return tables, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "annotation" || annotation > 0 {
+				// Comments hold paragraphs that are not part of the value
+				if t.Name.Local == "annotation" {
+					annotation++
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				tables = append(tables, Table{Name: odsAttr(t, "name")})
+				table = &tables[len(tables)-1]
+				emptyRows = 0
+			case "table-row":
+				row, emptyCells = nil, 0
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+			case "table-cell", "covered-table-cell":
+				cell = &strings.Builder{}
+				cellRepeat = odsRepeat(t, "number-columns-repeated")
+				href = ""
+			case "p", "h":
+				if cell != nil && cell.Len() > 0 {
+					cell.WriteString("\n")
+				}
+				paragraph++
+			case "s":
+				if cell != nil {
+					cell.WriteString(strings.Repeat(" ", odsRepeat(t, "c")))
+				}
+			case "a":
+				if target := odsAttr(t, "href"); cell != nil && href == "" && isWebLink(target) {
+					href = target
+				}
+			case "tab":
+				if cell != nil {
+					cell.WriteString("\t")
+				}
+			case "line-break":
+				if cell != nil {
+					cell.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if cell != nil && paragraph > 0 && annotation == 0 {
+				cell.Write(t)
+			}
+		case xml.EndElement:
+			if annotation > 0 {
+				if t.Name.Local == "annotation" {
+					annotation--
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "h":
+				paragraph--
+			case "table-cell", "covered-table-cell":
+				if cell == nil {
+					continue
+				}
+				value := cell.String()
+				cell = nil
+				// A cell holding a hyperlink is read as its target
+				if href != "" {
+					value = href
+				}
+				if value == "" {
+					emptyCells += cellRepeat
+					continue
+				}
+				for ; emptyCells > 0 && len(row) < odsMaxRepeat; emptyCells-- {
+					row = append(row, "")
+				}
+				for i := 0; i < cellRepeat && len(row) < odsMaxRepeat; i++ {
+					row = append(row, value)
+				}
+			case "table-row":
+				if table == nil {
+					continue
+				}
+				if len(row) == 0 {
+					emptyRows += rowRepeat
+					continue
+				}
+				for ; emptyRows > 0 && len(table.Rows) < odsMaxRepeat; emptyRows-- {
+					table.Rows = append(table.Rows, nil)
+				}
+				for i := 0; i < rowRepeat && len(table.Rows) < odsMaxRepeat; i++ {
+					table.Rows = append(table.Rows, row)
+				}
+			case "table":
+				table = nil
+			}
+		}
+	}
+}
+
+// isWebLink reports whether the target of a hyperlink is a web page, links to other cells are ignored
+func isWebLink(target string) bool {
+	target = strings.ToLower(target)
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
+
+// odsAttr returns the value of the attribute of the element by local name
+func odsAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat returns the repeat count of the element, 1 when it is not repeated
+func odsRepeat(element xml.StartElement, name string) int {
+	count, err := strconv.Atoi(odsAttr(element, name))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
@@ -0,0 +1,112 @@
+package filemanager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Reader reads the tables of an input format, the file manager finds the links in their rows.
+// The readers of a file manager are tried in order, the first one that detects the file reads it.
+type Reader interface {
+	// Extensions returns the lowercase file extensions of the format, like ".csv"
+	Extensions() []string
+	// Detect reports whether the file is in the format from its lowercase extension and its first bytes
+	Detect(ext string, head []byte) bool
+	// Read reads every table of the file, errors caused by its content wrap ErrInvalidFile
+	Read(filePath string) ([]Table, error)
+}
+
+// Table is a sheet of an input file as read by a Reader
+type Table struct {
+	Name     string     // Name of the sheet, "" for formats without sheets
+	Rows     [][]string // Cells of every row as text
+	Workbook bool       // Whether the file is an .xlsx workbook that the enriched output copies
+}
+
+// DefaultReaders returns the readers of the supported formats.
+// Binary formats come first, they are detected by their content whatever the extension of the file.
+func DefaultReaders() []Reader {
+	return []Reader{
+		&odsReader{},
+		&xlsxReader{},
+		&xlsReader{},
+		&delimitedReader{extensions: []string{".csv"}, comma: ','},
+		&delimitedReader{extensions: []string{".tsv", ".tab"}, comma: '\t'},
+		&lineReader{},
+		&jsonReader{},
+		&jsonLinesReader{},
+	}
+}
+
+var (
+	zipSignature = []byte("PK\x03\x04")
+	// cfbSignature starts the compound files of the legacy Office formats
+	cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+)
+
+// readerFor returns the reader of the file, detected from its first bytes and its extension
+func (fm *FileManagerImpl) readerFor(filePath string) (Reader, error) {
+	head, err := readHead(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, reader := range fm.readers {
+		if reader.Detect(ext, head) {
+			return reader, nil
+		}
+	}
+
+	// Exports named .xls are often HTML tables
+	if isMarkup(head) {
+		return nil, fmt.Errorf("%w: the file is an HTML or XML page, not a spreadsheet, open it in Excel and save it as .xlsx", ErrUnsupportedFormat)
+	}
+	for _, reader := range fm.readers {
+		if slices.Contains(reader.Extensions(), ext) {
+			return nil, fmt.Errorf("%w: the file is not a valid %s file", ErrInvalidFile, ext)
+		}
+	}
+	return nil, fm.unsupportedFormat(ext)
+}
+
+// unsupportedFormat is the error of the files that no reader can read
+func (fm *FileManagerImpl) unsupportedFormat(ext string) error {
+	var extensions []string
+	for _, reader := range fm.readers {
+		extensions = append(extensions, reader.Extensions()...)
+	}
+	return fmt.Errorf("%w: %q. Supported formats: %s", ErrUnsupportedFormat, ext, strings.Join(extensions, ", "))
+}
+
+// readHead reads the first bytes of the file to detect its format
+func readHead(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// isMarkup reports whether the file starts like an HTML or XML page, text readers don't read them
+func isMarkup(head []byte) bool {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte("<"))
+}
+
+// isBinary reports whether the file starts with the signature of a zip or compound file
+func isBinary(head []byte) bool {
+	return bytes.HasPrefix(head, zipSignature) || bytes.HasPrefix(head, cfbSignature)
+}
@@ -0,0 +1,203 @@
+package filemanager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeTestFile writes the content to a file of a temporary directory
+func writeTestFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReaderFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    Reader
+		wantErr error
+	}{
+		{"ods", testdata("links.ods"), &odsReader{}, nil},
+		{"xlsx", testdata("links.xlsx"), &xlsxReader{}, nil},
+		{"xls", testdata("links.xls"), &xlsReader{}, nil},
+		{"csv", testdata("links.csv"), &delimitedReader{}, nil},
+		{"xlsx renamed csv", testdata("links_xlsx.csv"), &xlsxReader{}, nil},
+		{"zip renamed csv", testdata("archive.csv"), &xlsxReader{}, nil},
+		{"html named xls", testdata("report.xls"), nil, ErrUnsupportedFormat},
+		{"html named csv", writeTestFile(t, "links.csv", "\ufeff  <!DOCTYPE html><table></table>"), nil, ErrUnsupportedFormat},
+		{"text named xls", writeTestFile(t, "links.xls", "https://t.me/durov"), nil, ErrInvalidFile},
+		{"tsv", writeTestFile(t, "links.tsv", "https://t.me/durov"), &delimitedReader{}, nil},
+		{"txt", writeTestFile(t, "links.txt", "https://t.me/durov"), &lineReader{}, nil},
+		{"json", writeTestFile(t, "links.json", `["https://t.me/durov"]`), &jsonReader{}, nil},
+		{"ndjson", writeTestFile(t, "links.ndjson", `"https://t.me/durov"`), &jsonLinesReader{}, nil},
+		{"upper case extension", writeTestFile(t, "LINKS.TXT", "https://t.me/durov"), &lineReader{}, nil},
+		{"unknown extension", testdata("links.pdf"), nil, ErrUnsupportedFormat},
+	}
+	fm := newTestFileManager().(*FileManagerImpl)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := fm.readerFor(tt.file)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readerFor error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				return
+			}
+			if got, want := readerName(reader), readerName(tt.want); got != want {
+				t.Errorf("readerFor = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// readerName names the type of the reader
+func readerName(reader Reader) string {
+	switch reader.(type) {
+	case *odsReader:
+		return "ods"
+	case *xlsxReader:
+		return "xlsx"
+	case *xlsReader:
+		return "xls"
+	case *delimitedReader:
+		return "delimited"
+	case *lineReader:
+		return "lines"
+	case *jsonReader:
+		return "json"
+	case *jsonLinesReader:
+		return "json lines"
+	}
+	return "unknown"
+}
+
+func TestODSReaderRead(t *testing.T) {
+	tables, err := (&odsReader{}).Read(testdata("links.ods"))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(tables) != 2 || tables[0].Name != "Channels" || tables[1].Name != "Summary" {
+		t.Fatalf("tables = %+v, want Channels and Summary", tables)
+	}
+
+	want := [][]string{
+		{"Name", "Link"},
+		// The label of the hyperlink is the name of the channel, the cell is read as its target
+		{"Durov", "https://t.me/durov"},
+		nil,
+		nil,
+		{"Team", "https://vk.com/team"},
+		// Comments are not part of the value
+		{"Plain", "https://t.me/plain"},
+		// Links to other cells keep their label
+		{"see the summary"},
+	}
+	rows := tables[0].Rows
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+	if summary := tables[1].Rows; len(summary) != 1 || !slices.Equal(summary[0], []string{"Total  3"}) {
+		t.Errorf("summary rows = %q, want the spaces of the text", summary)
+	}
+
+	links, err := newTestFileManager().ReadLinksFromFile(testdata("links.ods"), ReadOptions{Column: "Link", HeaderRow: 1})
+	if err != nil {
+		t.Fatalf("ReadLinksFromFile: %v", err)
+	}
+	if want := []string{"https://t.me/durov", "https://vk.com/team", "https://t.me/plain"}; !slices.Equal(links, want) {
+		t.Errorf("links = %q, want %q", links, want)
+	}
+}
+
+func TestTextReadersRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:    "json links and objects",
+			file:    "links.json",
+			content: `["https://t.me/durov", {"link": "https://vk.com/team", "name": "Team"}]`,
+			want:    [][]string{{"https://t.me/durov"}, {"https://vk.com/team"}},
+		},
+		{name: "json object", file: "links.json", content: `{"link": "https://t.me/durov"}`, wantErr: "JSON array"},
+		{name: "json malformed", file: "links.json", content: `["https://t.me/durov",`, wantErr: "JSON array"},
+		{name: "json number", file: "links.json", content: `["https://t.me/durov", 42]`, wantErr: "item 2"},
+		{name: "json object without link", file: "links.json", content: `[{"url": "https://t.me/durov"}]`, wantErr: "item 1"},
+		{
+			name:    "json lines with empty lines",
+			file:    "links.jsonl",
+			content: "\"https://t.me/durov\"\n\n{\"link\": \"https://vk.com/team\"}\r\n",
+			want:    [][]string{{"https://t.me/durov"}, {""}, {"https://vk.com/team"}},
+		},
+		{name: "json lines malformed", file: "links.jsonl", content: "\"https://t.me/durov\"\n[1]\n", wantErr: "line 2"},
+		{
+			name:    "text lines",
+			file:    "links.txt",
+			content: "\ufeffhttps://t.me/durov\r\n\n  https://vk.com/team  \n",
+			want:    [][]string{{"https://t.me/durov"}, {""}, {"https://vk.com/team"}},
+		},
+		{
+			name:    "tsv without quotes",
+			file:    "links.tsv",
+			content: "Name\tLink\nDurov \"the founder\"\thttps://t.me/durov\n",
+			want:    [][]string{{"Name", "Link"}, {`Durov "the founder"`, "https://t.me/durov"}},
+		},
+		{
+			name:    "csv with ragged rows",
+			file:    "links.csv",
+			content: "Link\nhttps://t.me/durov,Durov\n\"https://vk.com/team\"\n",
+			want:    [][]string{{"Link"}, {"https://t.me/durov", "Durov"}, {"https://vk.com/team"}},
+		},
+		{name: "text in windows-1251", file: "links.txt", content: "\xc4\xf3\xf0\xee\xe2 https://t.me/durov", wantErr: "UTF-8"},
+	}
+	fm := newTestFileManager().(*FileManagerImpl)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, tt.file, tt.content)
+			reader, err := fm.readerFor(path)
+			if err != nil {
+				t.Fatalf("readerFor: %v", err)
+			}
+			tables, err := reader.Read(path)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidFile) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read error = %v, want ErrInvalidFile about %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if len(tables) != 1 || tables[0].Name != "" {
+				t.Fatalf("tables = %+v, want a single table without name", tables)
+			}
+			rows := tables[0].Rows
+			if len(rows) != len(tt.want) {
+				t.Fatalf("rows = %q, want %q", rows, tt.want)
+			}
+			for i := range tt.want {
+				if !slices.Equal(rows[i], tt.want[i]) {
+					t.Errorf("row %d = %q, want %q", i, rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
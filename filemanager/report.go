@@ -0,0 +1,323 @@
+package filemanager
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheets of the Excel report
+const (
+	resultsSheet = "Results"
+	summarySheet = "Summary"
+)
+
+// thousandsFormat is the built in "#,##0" number format of Excel
+const thousandsFormat = 3
+
+// Columns of the results sheet, see ResultHeader
+const (
+	followersColumn = 2
+	linkColumn      = 3
+	statusColumn    = 5
+)
+
+// followerBuckets are the ranges of the followers distribution of the summary, the last one has no upper bound
+var followerBuckets = []struct {
+	label string
+	max   int64
+}{
+	{"Under 1K", 1_000},
+	{"1K - 10K", 10_000},
+	{"10K - 100K", 100_000},
+	{"100K - 1M", 1_000_000},
+	{"1M and more", math.MaxInt64},
+}
+
+// statusColors are the fill and font colors of the registration statuses
+var statusColors = []struct {
+	status database.Status
+	fill   string
+	font   string
+}{
+	{database.Registered, "#C6EFCE", "#006100"},
+	{database.NotRegistered, "#FFC7CE", "#9C0006"},
+	{database.NotApply, "#EDEDED", "#595959"},
+}
+
+// xlsxWriter writes an Excel report: the results with clickable links and color coded statuses, and a summary sheet
+type xlsxWriter struct{}
+
+var _ ResultWriter = (*xlsxWriter)(nil)
+
+// Format returns FormatXLSX
+func (xw *xlsxWriter) Format() string {
+	return FormatXLSX
+}
+
+// Write saves the results sheet and the summary sheet to an Excel file
+func (xw *xlsxWriter) Write(results []Result, outputPath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", resultsSheet); err != nil {
+		return fmt.Errorf("failed to create the results sheet: %w", err)
+	}
+	if err := writeResultsSheet(f, results); err != nil {
+		return fmt.Errorf("failed to write the results sheet: %w", err)
+	}
+	if _, err := f.NewSheet(summarySheet); err != nil {
+		return fmt.Errorf("failed to create the summary sheet: %w", err)
+	}
+	if err := writeSummarySheet(f, results); err != nil {
+		return fmt.Errorf("failed to write the summary sheet: %w", err)
+	}
+
+	// Save the Excel file
+	if err := f.SaveAs(outputPath); err != nil {
+		return fmt.Errorf("failed to save Excel file: %w", err)
+	}
+	return nil
+}
+
+// newHeaderStyle is the style of the header rows of the report
+func newHeaderStyle(f *excelize.File) (int, error) {
+	return f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+			Size: 12,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#DDEBF7"},
+			Pattern: 1,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+	})
+}
+
+// writeResultsSheet writes one row per link under a frozen header row with an autofilter
+func writeResultsSheet(f *excelize.File, results []Result) error {
+	headerStyle, err := newHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(&excelize.Style{NumFmt: thousandsFormat})
+	if err != nil {
+		return err
+	}
+	linkStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "#0563C1", Underline: "single"},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Set column headers to be wider
+	_ = f.SetColWidth(resultsSheet, "A", "A", 30)
+	_ = f.SetColWidth(resultsSheet, "B", "B", 15)
+	_ = f.SetColWidth(resultsSheet, "C", "C", 40)
+	_ = f.SetColWidth(resultsSheet, "D", "D", 12)
+	_ = f.SetColWidth(resultsSheet, "E", "E", 20)
+	_ = f.SetColWidth(resultsSheet, "F", "F", 40)
+	_ = f.SetColWidth(resultsSheet, "G", "G", 20)
+
+	_ = f.SetRowHeight(resultsSheet, 1, 20)
+	for c, title := range ResultHeader {
+		cellName, _ := excelize.CoordinatesToCellName(c+1, 1)
+		_ = f.SetCellValue(resultsSheet, cellName, title)
+	}
+	lastHeader, _ := excelize.CoordinatesToCellName(len(ResultHeader), 1)
+	_ = f.SetCellStyle(resultsSheet, "A1", lastHeader, headerStyle)
+
+	for r, result := range results {
+		for c, cellValue := range result.Row() {
+			cellName, _ := excelize.CoordinatesToCellName(c+1, r+2)
+			switch {
+			case c+1 == followersColumn && result.FollowersCount != nil:
+				_ = f.SetCellValue(resultsSheet, cellName, *result.FollowersCount)
+				_ = f.SetCellStyle(resultsSheet, cellName, cellName, numberStyle)
+			case c+1 == linkColumn && cellValue != "":
+				_ = f.SetCellValue(resultsSheet, cellName, cellValue)
+				if err := f.SetCellHyperLink(resultsSheet, cellName, cellValue, "External"); err == nil {
+					_ = f.SetCellStyle(resultsSheet, cellName, cellName, linkStyle)
+				}
+			default:
+				_ = f.SetCellValue(resultsSheet, cellName, cellValue)
+			}
+		}
+	}
+
+	lastRow := max(len(results)+1, 2)
+	lastCell, _ := excelize.CoordinatesToCellName(len(ResultHeader), lastRow)
+	if err := f.AutoFilter(resultsSheet, "A1:"+lastCell, nil); err != nil {
+		return err
+	}
+	if err := f.SetPanes(resultsSheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	// The statuses are colored with conditional formats so they follow the edits of the sheet
+	statusCol, _ := excelize.ColumnNumberToName(statusColumn)
+	statusRange := fmt.Sprintf("%s2:%s%d", statusCol, statusCol, lastRow)
+	formats := make([]excelize.ConditionalFormatOptions, 0, len(statusColors))
+	for _, color := range statusColors {
+		style, err := f.NewConditionalStyle(&excelize.Style{
+			Font: &excelize.Font{Color: color.font},
+			Fill: excelize.Fill{Type: "pattern", Color: []string{color.fill}, Pattern: 1},
+		})
+		if err != nil {
+			return err
+		}
+		formats = append(formats, excelize.ConditionalFormatOptions{
+			Type:     "cell",
+			Criteria: "==",
+			Format:   style,
+			Value:    fmt.Sprintf("%q", color.status.String()),
+		})
+	}
+	return f.SetConditionalFormat(resultsSheet, statusRange, formats)
+}
+
+// platformSummary is a row of the per platform table of the summary
+type platformSummary struct {
+	platform       string
+	links          int
+	channels       int
+	failed         int
+	registered     int
+	notRegistered  int
+	notApplicable  int
+	totalFollowers int64
+}
+
+// writeSummarySheet writes the totals per platform, the registration statuses and the followers distribution.
+// Duplicate links are counted once, in the links column only.
+func writeSummarySheet(f *excelize.File, results []Result) error {
+	headerStyle, err := newHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+	titleStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return err
+	}
+	numberStyle, err := f.NewStyle(&excelize.Style{NumFmt: thousandsFormat})
+	if err != nil {
+		return err
+	}
+
+	var platforms []*platformSummary
+	byPlatform := make(map[string]*platformSummary)
+	buckets := make([]int, len(followerBuckets))
+	unknownFollowers := 0
+	total := platformSummary{platform: "Total"}
+	for _, result := range results {
+		summary, ok := byPlatform[result.Platform]
+		if !ok {
+			summary = &platformSummary{platform: result.Platform}
+			byPlatform[result.Platform] = summary
+			platforms = append(platforms, summary)
+		}
+		for _, s := range []*platformSummary{summary, &total} {
+			s.links++
+		}
+		// Duplicates count as links, their channel is counted once with its first link
+		if result.DuplicateOf > 0 {
+			continue
+		}
+
+		for _, s := range []*platformSummary{summary, &total} {
+			s.channels++
+			switch {
+			case result.Error != "":
+				s.failed++
+			case result.RegistrationStatus == database.Registered:
+				s.registered++
+			case result.RegistrationStatus == database.NotRegistered:
+				s.notRegistered++
+			default:
+				s.notApplicable++
+			}
+			if result.FollowersCount != nil {
+				s.totalFollowers += *result.FollowersCount
+			}
+		}
+
+		if result.FollowersCount == nil {
+			unknownFollowers++
+			continue
+		}
+		for i, bucket := range followerBuckets {
+			if *result.FollowersCount < bucket.max {
+				buckets[i]++
+				break
+			}
+		}
+	}
+
+	_ = f.SetColWidth(summarySheet, "A", "A", 20)
+	_ = f.SetColWidth(summarySheet, "B", "H", 15)
+
+	row := 1
+	// table writes a titled table at row and moves row after it
+	table := func(title string, header []string, rows [][]any) {
+		titleCell, _ := excelize.CoordinatesToCellName(1, row)
+		_ = f.SetCellValue(summarySheet, titleCell, title)
+		_ = f.SetCellStyle(summarySheet, titleCell, titleCell, titleStyle)
+		row++
+		for c, value := range header {
+			cellName, _ := excelize.CoordinatesToCellName(c+1, row)
+			_ = f.SetCellValue(summarySheet, cellName, value)
+			_ = f.SetCellStyle(summarySheet, cellName, cellName, headerStyle)
+		}
+		row++
+		for _, values := range rows {
+			for c, value := range values {
+				cellName, _ := excelize.CoordinatesToCellName(c+1, row)
+				_ = f.SetCellValue(summarySheet, cellName, value)
+				if c > 0 {
+					_ = f.SetCellStyle(summarySheet, cellName, cellName, numberStyle)
+				}
+			}
+			row++
+		}
+		row++
+	}
+
+	platformRows := make([][]any, 0, len(platforms)+1)
+	for _, s := range append(platforms, &total) {
+		platformRows = append(platformRows, []any{
+			s.platform, s.links, s.channels, s.failed, s.registered, s.notRegistered, s.notApplicable, s.totalFollowers,
+		})
+	}
+	table("By platform",
+		[]string{"Platform", "Links", "Channels", "Failed", "Registered", "Not registered", "Not applicable", "Followers"},
+		platformRows)
+
+	table("Registration", []string{"Status", "Channels"}, [][]any{
+		{database.Registered.String(), total.registered},
+		{database.NotRegistered.String(), total.notRegistered},
+		{database.NotApply.String(), total.notApplicable},
+		{"failed", total.failed},
+	})
+
+	bucketRows := make([][]any, 0, len(followerBuckets)+1)
+	for i, bucket := range followerBuckets {
+		bucketRows = append(bucketRows, []any{bucket.label, buckets[i]})
+	}
+	bucketRows = append(bucketRows, []any{"Unknown", unknownFollowers})
+	table("Followers", []string{"Followers", "Channels"}, bucketRows)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package filemanager
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteSummarySheetCountsDuplicatesOnce(t *testing.T) {
+	durovFollowers, teamFollowers := int64(8871934), int64(5000)
+	results := []Result{
+		{ChannelName: "Durov", FollowersCount: &durovFollowers, Link: "https://t.me/durov", Platform: "telegram", RegistrationStatus: database.Registered},
+		{ChannelName: "Durov", FollowersCount: &durovFollowers, Link: "https://t.me/s/durov", Platform: "telegram", RegistrationStatus: database.Registered, Note: "duplicate of row 2", DuplicateOf: 1},
+		// A note on a link that is not a duplicate doesn't drop its channel
+		{ChannelName: "Team", FollowersCount: &teamFollowers, Link: "https://vk.com/team", Platform: "vk", RegistrationStatus: database.NotRegistered, Note: "checked by hand"},
+	}
+	outputPath := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := (&xlsxWriter{}).Write(results, outputPath); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	f, err := excelize.OpenFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows(summarySheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The labels of the summary rows are unique, the last row of a label is the one of the tables
+	byLabel := make(map[string][]string)
+	for _, row := range rows {
+		if len(row) > 1 {
+			byLabel[row[0]] = row[1:]
+		}
+	}
+
+	tests := []struct {
+		label string
+		want  []string // Links, channels, failed, registered, not registered, not applicable, followers; channels for the other tables
+	}{
+		{label: "telegram", want: []string{"2", "1", "0", "1", "0", "0", "8871934"}},
+		{label: "vk", want: []string{"1", "1", "0", "0", "1", "0", "5000"}},
+		{label: "Total", want: []string{"3", "2", "0", "1", "1", "0", "8876934"}},
+		{label: database.Registered.String(), want: []string{"1"}},
+		{label: database.NotRegistered.String(), want: []string{"1"}},
+		{label: "1K - 10K", want: []string{"1"}},
+		{label: "1M and more", want: []string{"1"}},
+		{label: "Unknown", want: []string{"0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := byLabel[tt.label]; !slices.Equal(got, tt.want) {
+				t.Errorf("row %q = %q, want %q", tt.label, got, tt.want)
+			}
+		})
+	}
+}
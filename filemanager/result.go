@@ -0,0 +1,70 @@
+package filemanager
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/database"
+)
+
+// ResultHeader is the header of the rows of Result.Row
+var ResultHeader = []string{"Channel Name", "Followers Count", "Original Link", "Platform", "Registration Status", "Error", "Note"}
+
+// Result is the result of a link with its typed fields, the result writers choose how to show them
+type Result struct {
+	ChannelName        string
+	FollowersCount     *int64 // nil when the followers could not be read
+	Link               string
+	Platform           string
+	RegistrationStatus database.Status // "" when the link could not be analyzed
+	Error              string
+	ErrorCode          string
+	Note               string    // "duplicate of row N" or "duplicate of item N" for the repeated links of a channel
+	DuplicateOf        int       // 1 based number of the first link of the channel, 0 when the link is the first
+	AnalyzedAt         time.Time // Date of the analysis, zero when unknown
+}
+
+// Row returns the result as displayed in the spreadsheets, in the columns of ResultHeader
+func (r Result) Row() []string {
+	followersCount := ""
+	if r.FollowersCount != nil {
+		followersCount = strconv.FormatInt(*r.FollowersCount, 10)
+	}
+	registrationStatus := ""
+	if r.RegistrationStatus != "" {
+		registrationStatus = r.RegistrationStatus.String()
+	}
+	return []string{r.ChannelName, followersCount, r.Link, r.Platform, registrationStatus, r.Error, r.Note}
+}
+
+// Record returns the result as stored with its job
+func (r Result) Record() database.JobResult {
+	return database.JobResult{
+		ChannelName:        r.ChannelName,
+		FollowersCount:     r.FollowersCount,
+		Link:               r.Link,
+		Platform:           r.Platform,
+		RegistrationStatus: r.RegistrationStatus,
+		Error:              r.Error,
+		ErrorCode:          r.ErrorCode,
+		Note:               r.Note,
+		DuplicateOf:        r.DuplicateOf,
+		AnalyzedAt:         r.AnalyzedAt,
+	}
+}
+
+// ResultFromRecord reads a result back from its record
+func ResultFromRecord(record database.JobResult) Result {
+	return Result{
+		ChannelName:        record.ChannelName,
+		FollowersCount:     record.FollowersCount,
+		Link:               record.Link,
+		Platform:           record.Platform,
+		RegistrationStatus: record.RegistrationStatus,
+		Error:              record.Error,
+		ErrorCode:          record.ErrorCode,
+		Note:               record.Note,
+		DuplicateOf:        record.DuplicateOf,
+		AnalyzedAt:         record.AnalyzedAt,
+	}
+}
@@ -0,0 +1,142 @@
+package filemanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// AllSheets is the value of the sheets option that reads every sheet of a workbook
+const AllSheets = "all"
+
+// ReadOptions selects the cells of a spreadsheet that hold the links.
+// The zero value reads every cell of the first sheet.
+type ReadOptions struct {
+	Sheets    []string // Names of the sheets to read, empty reads the first sheet
+	AllSheets bool     // Read every sheet of the workbook, Sheets is ignored
+	Column    string   // Header name or letter of the column of the links, empty reads every column
+	HeaderRow int      // 1 based row of the headers, it and the rows above it are skipped. 0 means no header row
+}
+
+// ParseSheets reads the sheets option of the upload form and the CLI:
+// "" is the first sheet, "all" every sheet, otherwise a comma separated list of names.
+func ParseSheets(value string) (sheets []string, all bool) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, AllSheets) {
+		return nil, true
+	}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			sheets = append(sheets, name)
+		}
+	}
+	return sheets, false
+}
+
+// Sheet is the content of a sheet of an input file, kept to write the results next to the original columns
+type Sheet struct {
+	Path      string      // Path of the input file
+	Name      string      // Name of the sheet of a workbook, "" for CSV and text input
+	Workbook  bool        // Whether the input is a workbook that the enriched output copies
+	HeaderRow int         // 1 based row of the headers, 0 when the sheet has none
+	Rows      [][]string  // Cells of every row, as read from the file
+	Links     []SheetLink // Links in reading order, the same order as ReadLinksFromFile
+}
+
+// SheetLink is a link found in a cell of a sheet
+type SheetLink struct {
+	Row  int    // 0 based index of the row in Sheet.Rows
+	Col  int    // 0 based index of the column
+	Link string // Normalized link
+}
+
+// selectSheets returns the names of the sheets of the workbook to read
+func selectSheets(available []string, opts ReadOptions) ([]string, error) {
+	if len(available) == 0 {
+		return nil, fmt.Errorf("%w: no sheets found in Excel file", ErrInvalidFile)
+	}
+	if opts.AllSheets {
+		return available, nil
+	}
+	if len(opts.Sheets) == 0 {
+		return available[:1], nil
+	}
+
+	selected := make([]string, 0, len(opts.Sheets))
+	for _, name := range opts.Sheets {
+		found := false
+		for _, sheet := range available {
+			if strings.EqualFold(sheet, name) {
+				selected = append(selected, sheet)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: sheet %q not found, the workbook has %s", ErrInvalidOptions, name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// resolveColumn returns the 0 based index of the column of the links, -1 when every column is read.
+// The column is looked up by name in the header row first, then read as a column letter. Only the letters
+// of the columns of the sheet are accepted, so a header name like "URL" or "TG" is never read as a letter.
+func resolveColumn(rows [][]string, opts ReadOptions) (int, error) {
+	column := strings.TrimSpace(opts.Column)
+	if column == "" {
+		return -1, nil
+	}
+	if opts.HeaderRow > 0 && opts.HeaderRow <= len(rows) {
+		for c, header := range rows[opts.HeaderRow-1] {
+			if strings.EqualFold(strings.TrimSpace(header), column) {
+				return c, nil
+			}
+		}
+	}
+	if number, err := excelize.ColumnNameToNumber(column); err == nil && (len(rows) == 0 || number <= sheetWidth(rows)) {
+		return number - 1, nil
+	}
+	if opts.HeaderRow == 0 {
+		return -1, fmt.Errorf("%w: column %q is not a column letter, set the header row to find it by name", ErrInvalidOptions, column)
+	}
+	return -1, fmt.Errorf("%w: column %q not found in header row %d", ErrInvalidOptions, column, opts.HeaderRow)
+}
+
+// sheetWidth returns the number of columns of the widest row
+func sheetWidth(rows [][]string) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+// findLinks returns the supported links of the rows after the header row, row by row and from left to right.
+// Only the given column is read unless it is -1.
+func (fm *FileManagerImpl) findLinks(rows [][]string, column int, headerRow int) []SheetLink {
+	links := make([]SheetLink, 0)
+	for r := headerRow; r < len(rows); r++ {
+		for c, cell := range rows[r] {
+			if column >= 0 && c != column {
+				continue
+			}
+			if fm.registry.IsSupported(cell) {
+				links = append(links, SheetLink{Row: r, Col: c, Link: fm.registry.Normalize(cell)})
+			}
+		}
+	}
+	return links
+}
+
+// linkValues returns the normalized links of the sheets
+func linkValues(sheets []*Sheet) []string {
+	links := make([]string, 0)
+	for _, sheet := range sheets {
+		for _, link := range sheet.Links {
+			links = append(links, link.Link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,59 @@
+package filemanager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveColumn(t *testing.T) {
+	rows := [][]string{
+		{"Name", "Link", "VK", "A"},
+		{"Durov", "https://t.me/durov", "https://vk.com/durov", "x"},
+		{"Team", "https://t.me/team", "", "", "notes"},
+	}
+	tests := []struct {
+		name      string
+		column    string
+		headerRow int
+		want      int
+		wantErr   string
+	}{
+		{"every column", "", 1, -1, ""},
+		{"header name", "link", 1, 1, ""},
+		{"header name with spaces", " Link ", 1, 1, ""},
+		{"header like a letter", "VK", 1, 2, ""},
+		{"header matches before the letter", "a", 1, 3, ""},
+		{"letter with header row", "B", 1, 1, ""},
+		{"letter of the widest row", "E", 1, 4, ""},
+		{"letter without header row", "c", 0, 2, ""},
+		{"unknown name", "URL", 1, 0, `column "URL" not found in header row 1`},
+		{"unknown short name", "TG", 1, 0, `column "TG" not found in header row 1`},
+		{"letter past the last column", "F", 1, 0, `column "F" not found in header row 1`},
+		{"name without header row", "Link", 0, 0, "set the header row"},
+		{"short name without header row", "URL", 0, 0, "set the header row"},
+		{"header row past the rows", "Link", 10, 0, `column "Link" not found in header row 10`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveColumn(rows, ReadOptions{Column: tt.column, HeaderRow: tt.headerRow})
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidOptions) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveColumn error = %v, want ErrInvalidOptions with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveColumn: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveColumn = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// An empty sheet has no links whatever the column
+	if got, err := resolveColumn(nil, ReadOptions{Column: "B"}); err != nil || got != 1 {
+		t.Errorf("resolveColumn on an empty sheet = %d, %v, want column B", got, err)
+	}
+}
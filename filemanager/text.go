@@ -0,0 +1,77 @@
+package filemanager
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// delimitedReader reads CSV and TSV files, they must be encoded in UTF-8
+type delimitedReader struct {
+	extensions []string
+	comma      rune
+}
+
+var _ Reader = (*delimitedReader)(nil)
+
+// Extensions returns the extensions of the format
+func (dr *delimitedReader) Extensions() []string {
+	return dr.extensions
+}
+
+// Detect detects the format by extension, workbooks and HTML pages named like a delimited file are not read
+func (dr *delimitedReader) Detect(ext string, head []byte) bool {
+	return slices.Contains(dr.extensions, ext) && !isBinary(head) && !isMarkup(head)
+}
+
+// Read reads the rows of the file as a single table
+func (dr *delimitedReader) Read(filePath string) ([]Table, error) {
+	content, err := readText(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = dr.comma
+	// Rows of a spreadsheet export don't always have the same number of cells
+	reader.FieldsPerRecord = -1
+	// Tab separated exports don't quote their cells
+	reader.LazyQuotes = dr.comma == '\t'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed %s file: %v", ErrInvalidFile, strings.ToUpper(strings.TrimPrefix(dr.extensions[0], ".")), err)
+	}
+
+	return []Table{{Rows: records}}, nil
+}
+
+// lineReader reads text files with one link per line
+type lineReader struct{}
+
+var _ Reader = (*lineReader)(nil)
+
+// Extensions returns the extension of text files
+func (lr *lineReader) Extensions() []string {
+	return []string{".txt"}
+}
+
+// Detect detects text files by extension
+func (lr *lineReader) Detect(ext string, head []byte) bool {
+	return ext == ".txt" && !isBinary(head) && !isMarkup(head)
+}
+
+// Read reads every line as a row with a single cell, empty lines are kept so row numbers match the file
+func (lr *lineReader) Read(filePath string) ([]Table, error) {
+	content, err := readText(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\r\n"), "\n")
+	rows := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		rows = append(rows, []string{strings.TrimSpace(line)})
+	}
+	return []Table{{Rows: rows}}, nil
+}
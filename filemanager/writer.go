@@ -0,0 +1,205 @@
+package filemanager
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the output files
+const (
+	FormatXLSX  = "xlsx"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+)
+
+// ResultWriter writes the results of a run to an output file
+type ResultWriter interface {
+	// Format returns the name of the format, it is also the extension of its files
+	Format() string
+	// Write writes the results in the order of the input links
+	Write(results []Result, outputPath string) error
+}
+
+// NewResultWriter returns the writer of the format, "" is FormatXLSX
+func NewResultWriter(format string) (ResultWriter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatXLSX:
+		return &xlsxWriter{}, nil
+	case FormatCSV:
+		return &csvWriter{}, nil
+	case FormatJSON:
+		return &jsonWriter{}, nil
+	case FormatJSONL:
+		return &jsonLinesWriter{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown output format %q, use %s, %s, %s or %s", ErrInvalidOptions, format, FormatXLSX, FormatCSV, FormatJSON, FormatJSONL)
+	}
+}
+
+// ParseFormat validates the format option of the upload form and the CLI, "" is FormatXLSX
+func ParseFormat(format string) (string, error) {
+	writer, err := NewResultWriter(format)
+	if err != nil {
+		return "", err
+	}
+	return writer.Format(), nil
+}
+
+// typedHeader is the header of the formats that keep the typed fields
+var typedHeader = []string{"channel_name", "followers_count", "link", "platform", "registration_status", "error", "error_code", "note", "analyzed_at"}
+
+// typedRecord is a result as written by the JSON formats, the dates are in ISO 8601
+type typedRecord struct {
+	ChannelName        string  `json:"channel_name"`
+	FollowersCount     *int64  `json:"followers_count"`
+	Link               string  `json:"link"`
+	Platform           string  `json:"platform"`
+	RegistrationStatus *string `json:"registration_status"`
+	Error              string  `json:"error,omitempty"`
+	ErrorCode          string  `json:"error_code,omitempty"`
+	Note               string  `json:"note,omitempty"`
+	AnalyzedAt         *string `json:"analyzed_at"`
+}
+
+func newTypedRecord(result Result) typedRecord {
+	record := typedRecord{
+		ChannelName:    result.ChannelName,
+		FollowersCount: result.FollowersCount,
+		Link:           result.Link,
+		Platform:       result.Platform,
+		Error:          result.Error,
+		ErrorCode:      result.ErrorCode,
+		Note:           result.Note,
+	}
+	if result.RegistrationStatus != "" {
+		status := string(result.RegistrationStatus)
+		record.RegistrationStatus = &status
+	}
+	if !result.AnalyzedAt.IsZero() {
+		analyzedAt := result.AnalyzedAt.UTC().Format(time.RFC3339)
+		record.AnalyzedAt = &analyzedAt
+	}
+	return record
+}
+
+// csvWriter writes the typed fields of the results as CSV, empty cells are the unknown values
+type csvWriter struct{}
+
+var _ ResultWriter = (*csvWriter)(nil)
+
+// Format returns FormatCSV
+func (cw *csvWriter) Format() string {
+	return FormatCSV
+}
+
+// Write saves the results to a CSV file with a header row
+func (cw *csvWriter) Write(results []Result, outputPath string) error {
+	return writeFile(outputPath, func(w *bufio.Writer) error {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(typedHeader); err != nil {
+			return err
+		}
+		for _, result := range results {
+			record := newTypedRecord(result)
+			followersCount, registrationStatus, analyzedAt := "", "", ""
+			if record.FollowersCount != nil {
+				followersCount = strconv.FormatInt(*record.FollowersCount, 10)
+			}
+			if record.RegistrationStatus != nil {
+				registrationStatus = *record.RegistrationStatus
+			}
+			if record.AnalyzedAt != nil {
+				analyzedAt = *record.AnalyzedAt
+			}
+			if err := writer.Write([]string{
+				record.ChannelName,
+				followersCount,
+				record.Link,
+				record.Platform,
+				registrationStatus,
+				record.Error,
+				record.ErrorCode,
+				record.Note,
+				analyzedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+}
+
+// jsonWriter writes the results as a JSON array of objects, unknown values are null
+type jsonWriter struct{}
+
+var _ ResultWriter = (*jsonWriter)(nil)
+
+// Format returns FormatJSON
+func (jw *jsonWriter) Format() string {
+	return FormatJSON
+}
+
+// Write saves the results to a JSON file
+func (jw *jsonWriter) Write(results []Result, outputPath string) error {
+	records := make([]typedRecord, 0, len(results))
+	for _, result := range results {
+		records = append(records, newTypedRecord(result))
+	}
+	return writeFile(outputPath, func(w *bufio.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	})
+}
+
+// jsonLinesWriter writes the results as JSON Lines, one object per link
+type jsonLinesWriter struct{}
+
+var _ ResultWriter = (*jsonLinesWriter)(nil)
+
+// Format returns FormatJSONL
+func (jw *jsonLinesWriter) Format() string {
+	return FormatJSONL
+}
+
+// Write saves the results to a JSON Lines file
+func (jw *jsonLinesWriter) Write(results []Result, outputPath string) error {
+	return writeFile(outputPath, func(w *bufio.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, result := range results {
+			if err := encoder.Encode(newTypedRecord(result)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeFile creates the output file and writes it through a buffer
+func writeFile(outputPath string, write func(w *bufio.Writer) error) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if err := write(w); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+package filemanager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// BIFF8 records read from the workbook stream, see [MS-XLS] 2.3
+const (
+	recordFormula    = 0x0006
+	recordEOF        = 0x000A
+	recordFilePass   = 0x002F
+	recordContinue   = 0x003C
+	recordBoundSheet = 0x0085
+	recordMulRK      = 0x00BD
+	recordSST        = 0x00FC
+	recordLabelSST   = 0x00FD
+	recordNumber     = 0x0203
+	recordLabel      = 0x0204
+	recordString     = 0x0207
+	recordRK         = 0x027E
+	recordBOF        = 0x0809
+
+	biff8Version = 0x0600
+)
+
+// xlsMaxColumns is the number of columns of a BIFF8 worksheet
+const xlsMaxColumns = 256
+
+// errCorruptXLS is returned for workbook streams that end in the middle of a record or hold cells Excel never writes
+var errCorruptXLS = errors.New("corrupt BIFF8 workbook")
+
+// boundSheet locates a sheet in the workbook stream
+type boundSheet struct {
+	name   string
+	offset int
+	kind   byte // 0 is a worksheet, charts and macro sheets are skipped
+}
+
+// xlsReader reads legacy Excel 97-2003 workbooks, their cells are read as text
+type xlsReader struct{}
+
+var _ Reader = (*xlsReader)(nil)
+
+// Extensions returns the extension of legacy Excel workbooks
+func (xr *xlsReader) Extensions() []string {
+	return []string{".xls"}
+}
+
+// Detect detects the compound files of the legacy Office formats
+func (xr *xlsReader) Detect(ext string, head []byte) bool {
+	return bytes.HasPrefix(head, cfbSignature)
+}
+
+// Read reads the worksheets of a BIFF8 workbook, chart and macro sheets are skipped
+func (xr *xlsReader) Read(filePath string) ([]Table, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := mscfb.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't open the Excel 97-2003 workbook: %v", ErrInvalidFile, err)
+	}
+
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "Workbook":
+			if stream, err = io.ReadAll(entry); err != nil {
+				return nil, fmt.Errorf("%w: can't read the workbook stream: %v", ErrInvalidFile, err)
+			}
+		case "Book":
+			return nil, fmt.Errorf("%w: Excel 5.0/95 workbooks are not supported, save the file as .xlsx", ErrUnsupportedFormat)
+		case "EncryptedPackage":
+			return nil, fmt.Errorf("%w: the workbook is password protected", ErrInvalidFile)
+		}
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("%w: the file is not an Excel workbook", ErrInvalidFile)
+	}
+
+	sheets, err := parseBIFF8(stream)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return sheets, nil
+}
+
+// parseBIFF8 reads the globals substream, then the cells of every worksheet
+func parseBIFF8(stream []byte) ([]Table, error) {
+	var bounds []boundSheet
+	var sst []string
+
+	typ, data, pos, err := nextRecord(stream, 0)
+	if err != nil {
+		return nil, err
+	}
+	if typ != recordBOF || len(data) < 2 || binary.LittleEndian.Uint16(data) != biff8Version {
+		return nil, errors.New("only Excel 97-2003 (BIFF8) workbooks are supported")
+	}
+
+	for typ != recordEOF && pos < len(stream) {
+		if typ, data, pos, err = nextRecord(stream, pos); err != nil {
+			return nil, err
+		}
+		switch typ {
+		case recordFilePass:
+			return nil, errors.New("the workbook is password protected")
+		case recordBoundSheet:
+			if len(data) < 8 {
+				return nil, errCorruptXLS
+			}
+			name, _, err := readString(data[6:], 1)
+			if err != nil {
+				return nil, err
+			}
+			bounds = append(bounds, boundSheet{
+				name:   name,
+				offset: int(binary.LittleEndian.Uint32(data)),
+				kind:   data[5],
+			})
+		case recordSST:
+			// The shared strings go on in the CONTINUE records that follow
+			if len(data) < 8 {
+				return nil, errCorruptXLS
+			}
+			segments := [][]byte{data[8:]}
+			for pos < len(stream) {
+				next, continued, after, err := nextRecord(stream, pos)
+				if err != nil || next != recordContinue {
+					break
+				}
+				segments = append(segments, continued)
+				pos = after
+			}
+			if sst, err = readSST(segments, int(binary.LittleEndian.Uint32(data[4:]))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sheets := make([]Table, 0, len(bounds))
+	for _, bound := range bounds {
+		if bound.kind != 0 {
+			continue
+		}
+		rows, err := parseWorksheet(stream, bound.offset, sst)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", bound.name, err)
+		}
+		sheets = append(sheets, Table{Name: bound.name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// parseWorksheet reads the cells of the worksheet substream starting at offset
+func parseWorksheet(stream []byte, offset int, sst []string) ([][]string, error) {
+	cells := make(map[int]map[int]string)
+	// The rows are sized by their last column, columns past the BIFF8 limit are never written by Excel
+	wideColumn := false
+	set := func(row, col int, value string) {
+		if col >= xlsMaxColumns {
+			wideColumn = true
+			return
+		}
+		if value == "" {
+			return
+		}
+		if cells[row] == nil {
+			cells[row] = make(map[int]string)
+		}
+		cells[row][col] = value
+	}
+
+	if offset < 0 || offset >= len(stream) {
+		return nil, errCorruptXLS
+	}
+	// A string formula result is stored in the STRING record that follows its FORMULA record
+	pendingRow, pendingCol := -1, -1
+	for pos := offset; pos < len(stream); {
+		typ, data, next, err := nextRecord(stream, pos)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+		if typ == recordEOF {
+			break
+		}
+		if len(data) < 4 && typ != recordString {
+			continue
+		}
+
+		switch typ {
+		case recordLabelSST:
+			if len(data) < 10 {
+				return nil, errCorruptXLS
+			}
+			if index := int(binary.LittleEndian.Uint32(data[6:])); index < len(sst) {
+				set(cellPosition(data)[0], cellPosition(data)[1], sst[index])
+			}
+		case recordLabel:
+			if len(data) < 8 {
+				return nil, errCorruptXLS
+			}
+			value, _, err := readString(data[6:], 2)
+			if err != nil {
+				return nil, err
+			}
+			set(cellPosition(data)[0], cellPosition(data)[1], value)
+		case recordNumber:
+			if len(data) < 14 {
+				return nil, errCorruptXLS
+			}
+			set(cellPosition(data)[0], cellPosition(data)[1], formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(data[6:]))))
+		case recordRK:
+			if len(data) < 10 {
+				return nil, errCorruptXLS
+			}
+			set(cellPosition(data)[0], cellPosition(data)[1], formatNumber(decodeRK(binary.LittleEndian.Uint32(data[6:]))))
+		case recordMulRK:
+			// row, first column, then an (ixfe, rk) pair per column and the last column
+			position := cellPosition(data)
+			for i, p := 0, 4; p+6 <= len(data)-2; i, p = i+1, p+6 {
+				set(position[0], position[1]+i, formatNumber(decodeRK(binary.LittleEndian.Uint32(data[p+2:]))))
+			}
+		case recordFormula:
+			if len(data) < 14 {
+				return nil, errCorruptXLS
+			}
+			position := cellPosition(data)
+			result := data[6:14]
+			if result[6] != 0xFF || result[7] != 0xFF {
+				set(position[0], position[1], formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(result))))
+			} else if result[0] == 0 {
+				pendingRow, pendingCol = position[0], position[1]
+			}
+		case recordString:
+			if pendingRow >= 0 {
+				value, _, err := readString(data, 2)
+				if err != nil {
+					return nil, err
+				}
+				set(pendingRow, pendingCol, value)
+			}
+			pendingRow, pendingCol = -1, -1
+		}
+	}
+
+	if wideColumn {
+		return nil, fmt.Errorf("%w: cell past column %d", errCorruptXLS, xlsMaxColumns)
+	}
+
+	// Rows are returned like excelize GetRows: up to the last row with a value, without trailing empty cells
+	lastRow := -1
+	for row := range cells {
+		lastRow = max(lastRow, row)
+	}
+	rows := make([][]string, lastRow+1)
+	for row, values := range cells {
+		lastCol := -1
+		for col := range values {
+			lastCol = max(lastCol, col)
+		}
+		rows[row] = make([]string, lastCol+1)
+		for col, value := range values {
+			rows[row][col] = value
+		}
+	}
+	return rows, nil
+}
+
+// nextRecord reads the record at pos, it returns its type, its data and the position of the next record
+func nextRecord(stream []byte, pos int) (uint16, []byte, int, error) {
+	if pos+4 > len(stream) {
+		return 0, nil, 0, errCorruptXLS
+	}
+	typ := binary.LittleEndian.Uint16(stream[pos:])
+	size := int(binary.LittleEndian.Uint16(stream[pos+2:]))
+	end := pos + 4 + size
+	if end > len(stream) {
+		return 0, nil, 0, errCorruptXLS
+	}
+	return typ, stream[pos+4 : end], end, nil
+}
+
+// cellPosition returns the row and the column of a cell record
+func cellPosition(data []byte) [2]int {
+	return [2]int{int(binary.LittleEndian.Uint16(data)), int(binary.LittleEndian.Uint16(data[2:]))}
+}
+
+// readString reads an unformatted unicode string whose length takes lengthSize bytes, it returns the
+// string and the number of bytes read
+func readString(data []byte, lengthSize int) (string, int, error) {
+	if len(data) < lengthSize+1 {
+		return "", 0, errCorruptXLS
+	}
+	count := int(data[0])
+	if lengthSize == 2 {
+		count = int(binary.LittleEndian.Uint16(data))
+	}
+	high := data[lengthSize]&0x01 != 0
+	start := lengthSize + 1
+	size := count
+	if high {
+		size *= 2
+	}
+	if start+size > len(data) {
+		return "", 0, errCorruptXLS
+	}
+	return decodeChars(data[start:start+size], high), start + size, nil
+}
+
+// decodeChars decodes the characters of a string, compressed strings hold the low byte of every character
+func decodeChars(data []byte, high bool) string {
+	if !high {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// sstReader reads the shared strings table across the CONTINUE records it is split into
+type sstReader struct {
+	segments [][]byte
+	segment  int
+	pos      int
+}
+
+// skip moves past n bytes without reading them, the lengths of the skipped fields come from the file
+func (r *sstReader) skip(n int) error {
+	for n > 0 {
+		if r.segment >= len(r.segments) {
+			return errCorruptXLS
+		}
+		if r.pos >= len(r.segments[r.segment]) {
+			r.segment++
+			r.pos = 0
+			continue
+		}
+		take := min(n, len(r.segments[r.segment])-r.pos)
+		r.pos += take
+		n -= take
+	}
+	return nil
+}
+
+// bytes reads n bytes, the fields other than the characters of a string continue in the next record as is
+func (r *sstReader) bytes(n int) ([]byte, error) {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		if r.segment >= len(r.segments) {
+			return nil, errCorruptXLS
+		}
+		current := r.segments[r.segment]
+		if r.pos >= len(current) {
+			r.segment++
+			r.pos = 0
+			continue
+		}
+		take := min(n-len(out), len(current)-r.pos)
+		out = append(out, current[r.pos:r.pos+take]...)
+		r.pos += take
+	}
+	return out, nil
+}
+
+// chars reads count characters, a record that continues the characters starts with their new flags
+func (r *sstReader) chars(count int, high bool) (string, error) {
+	var text string
+	for count > 0 {
+		if r.segment >= len(r.segments) {
+			return "", errCorruptXLS
+		}
+		current := r.segments[r.segment]
+		if r.pos >= len(current) {
+			r.segment++
+			if r.segment >= len(r.segments) || len(r.segments[r.segment]) == 0 {
+				return "", errCorruptXLS
+			}
+			high = r.segments[r.segment][0]&0x01 != 0
+			r.pos = 1
+			continue
+		}
+		width := 1
+		if high {
+			width = 2
+		}
+		available := min(count, (len(current)-r.pos)/width)
+		if available == 0 {
+			return "", errCorruptXLS
+		}
+		text += decodeChars(current[r.pos:r.pos+available*width], high)
+		r.pos += available * width
+		count -= available
+	}
+	return text, nil
+}
+
+// readSST reads the unique strings of the shared strings table
+func readSST(segments [][]byte, unique int) ([]string, error) {
+	r := &sstReader{segments: segments}
+	strings := make([]string, 0, min(unique, 1<<16))
+	for i := 0; i < unique; i++ {
+		header, err := r.bytes(3)
+		if err != nil {
+			return nil, err
+		}
+		count := int(binary.LittleEndian.Uint16(header))
+		flags := header[2]
+
+		// Formatting runs and phonetic data follow the characters, they are skipped
+		var runs, extra int
+		if flags&0x08 != 0 {
+			b, err := r.bytes(2)
+			if err != nil {
+				return nil, err
+			}
+			runs = int(binary.LittleEndian.Uint16(b))
+		}
+		if flags&0x04 != 0 {
+			b, err := r.bytes(4)
+			if err != nil {
+				return nil, err
+			}
+			extra = int(binary.LittleEndian.Uint32(b))
+		}
+
+		text, err := r.chars(count, flags&0x01 != 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.skip(4*runs + extra); err != nil {
+			return nil, err
+		}
+		strings = append(strings, text)
+	}
+	return strings, nil
+}
+
+// decodeRK decodes the compact number format of the RK and MULRK records
+func decodeRK(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+// formatNumber formats a number cell the way it is shown without number format
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,125 @@
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestXLSReaderRead(t *testing.T) {
+	tables, err := (&xlsReader{}).Read(testdata("links.xls"))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	// The chart sheet is skipped
+	if len(tables) != 1 || tables[0].Name != "Links" {
+		t.Fatalf("tables = %d, want only the worksheet Links", len(tables))
+	}
+	rows := tables[0].Rows
+	if len(rows) != 303 {
+		t.Fatalf("read %d rows, want 303", len(rows))
+	}
+
+	tests := []struct {
+		name string
+		row  int
+		want []string
+	}{
+		{"shared strings", 0, []string{"Name", "Link", "Followers"}},
+		{"UTF-16 rich text and a number", 1, []string{"Канал Дурова", "https://t.me/channel_000_with_a_long_handle", "8871934"}},
+		{"integer RK", 2, []string{"", "https://t.me/channel_001_with_a_long_handle", "12345"}},
+		{"RK divided by 100", 3, []string{"", "https://t.me/channel_002_with_a_long_handle", "1234.5"}},
+		// Both strings are split across the SST record and a CONTINUE record
+		{"compressed string continued", 178, []string{"Канал 177", "https://t.me/channel_177_with_a_long_handle", "181000.5"}},
+		{"UTF-16 string continued", 120, []string{"Канал 119", "https://t.me/channel_119_with_a_long_handle", "123000.5"}},
+		{"UTF-16 shared string", 301, []string{"Новости — https://vk.com/новости"}},
+		{"label, string formula and MULRK", 302, []string{"Total", "https://vk.com/formula", "42", "7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(rows[tt.row], tt.want) {
+				t.Errorf("row %d = %q, want %q", tt.row, rows[tt.row], tt.want)
+			}
+		})
+	}
+
+	for r := 4; r <= 300; r++ {
+		want := []string{fmt.Sprintf("Канал %03d", r-1), fmt.Sprintf("https://t.me/channel_%03d_with_a_long_handle", r-1), strconv.Itoa((r+3)*1000) + ".5"}
+		if !slices.Equal(rows[r], want) {
+			t.Fatalf("row %d = %q, want %q", r, rows[r], want)
+		}
+	}
+}
+
+func TestReadLinksFromXLS(t *testing.T) {
+	links, err := newTestFileManager().ReadLinksFromFile(testdata("links.xls"), ReadOptions{Column: "Link", HeaderRow: 1})
+	if err != nil {
+		t.Fatalf("ReadLinksFromFile: %v", err)
+	}
+	if len(links) != 301 || links[0] != "https://t.me/channel_000_with_a_long_handle" || links[300] != "https://vk.com/formula" {
+		t.Errorf("read %d links (%q ... %q), want the 300 channels and the formula", len(links), links[0], links[len(links)-1])
+	}
+}
+
+func TestXLSReaderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantErr  error
+		wantText string
+	}{
+		{"stream ends inside a record", "corrupt.xls", ErrInvalidFile, "corrupt"},
+		{"shared string skips past the table", "sst_overflow.xls", ErrInvalidFile, "corrupt"},
+		{"cells past the last column", "wide_columns.xls", ErrInvalidFile, "past column 256"},
+		{"encrypted workbook", "encrypted.xls", ErrInvalidFile, "password protected"},
+		{"Excel 5.0 workbook", "biff5.xls", ErrUnsupportedFormat, "Excel 5.0/95"},
+		{"not a compound file", "links.csv", ErrInvalidFile, "Excel 97-2003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&xlsReader{}).Read(testdata(tt.file))
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantText) {
+				t.Fatalf("Read error = %v, want %v about %q", err, tt.wantErr, tt.wantText)
+			}
+			if !IsValidationError(err) {
+				t.Errorf("IsValidationError(%v) = false, want true", err)
+			}
+		})
+	}
+}
+
+func TestReadSSTSkipsRunsAndPhonetics(t *testing.T) {
+	// "ab" with one formatting run and 3 bytes of phonetic data, the run continues in the next record
+	rich := [][]byte{
+		{2, 0, 0x0C, 1, 0, 3, 0, 0, 0, 'a', 'b', 0, 0},
+		{1, 0, 0xAA, 0xBB, 0xCC, 1, 0, 0, 'c'},
+	}
+	tests := []struct {
+		name     string
+		segments [][]byte
+		unique   int
+		want     []string
+		wantErr  bool
+	}{
+		{name: "runs across records", segments: rich, unique: 2, want: []string{"ab", "c"}},
+		{name: "runs past the table", segments: [][]byte{{2, 0, 0x08, 0xFF, 0xFF, 'a', 'b', 0, 0, 0, 0}}, unique: 1, wantErr: true},
+		{name: "phonetics past the table", segments: [][]byte{{2, 0, 0x04, 0xFF, 0xFF, 0xFF, 0xFF, 'a', 'b'}}, unique: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readSST(tt.segments, tt.unique)
+			if tt.wantErr {
+				if !errors.Is(err, errCorruptXLS) {
+					t.Fatalf("readSST error = %v, want errCorruptXLS", err)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Fatalf("readSST = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package filemanager
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxReader reads Office Open XML workbooks
+type xlsxReader struct{}
+
+var _ Reader = (*xlsxReader)(nil)
+
+// Extensions returns the extension of Excel workbooks
+func (xr *xlsxReader) Extensions() []string {
+	return []string{".xlsx"}
+}
+
+// Detect detects any zip archive, OpenDocument files are detected by the reader before it
+func (xr *xlsxReader) Detect(ext string, head []byte) bool {
+	return bytes.HasPrefix(head, zipSignature)
+}
+
+// Read reads the rows of every sheet of the workbook
+func (xr *xlsxReader) Read(filePath string) ([]Table, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't open the Excel file: %v", ErrInvalidFile, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("Failed to close Excel file: %v", err)
+		}
+	}()
+
+	tables := make([]Table, 0)
+	for _, name := range f.GetSheetList() {
+		rows, err := f.GetRows(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: can't read the rows of sheet %s: %v", ErrInvalidFile, name, err)
+		}
+		tables = append(tables, Table{Name: name, Rows: rows, Workbook: true})
+	}
+	return tables, nil
+}
@@ -400,7 +400,10 @@ document.getElementById('uploadForm').addEventListener('submit', async function(
             throw new Error('Upload/analysis failed');
         }
         
-        const uploadData = await uploadResponse.json();
+        const uploadJob = await uploadResponse.json();
+
+        // The analysis runs in the background, poll the job until it is done
+        const uploadData = await waitForJob(uploadJob.jobId);
         document.getElementById('loader').style.display = 'none';
         document.getElementById('resultsContainer').style.display = 'block';
 
@@ -417,33 +420,15 @@ document.getElementById('uploadForm').addEventListener('submit', async function(
                 <td class="count-up" data-count="${result[1]}">${result[1]}</td>
                 <td><a href="${result[2]}" target="_blank">${result[2]}</a></td>
                 <td><span class="badge badge-${result[3].toLowerCase()}">${result[3]}</span></td>
-                <td>${result[4]}</td>
+                <td>${result[5] ? '⚠️ ' + result[5] : result[4]}</td>
             `;
             resultsTableBody.appendChild(row);
         });
 
         document.getElementById('downloadButton').addEventListener('click', function() {
-            const token = DashboardAuthManager.getToken();
-            const downloadUrl = `/api/v1/influencers/download?filename=${uploadData.outputFile}`;
-            
-            // Create a temporary link with auth header for download
-            fetch(downloadUrl, {
-                headers: DashboardAuthManager.getAuthHeaders()
-            })
-            .then(response => response.blob())
-            .then(blob => {
-                const url = window.URL.createObjectURL(blob);
-                const a = document.createElement('a');
-                a.href = url;
-                a.download = uploadData.outputFile;
-                document.body.appendChild(a);
-                a.click();
-                window.URL.revokeObjectURL(url);
-                document.body.removeChild(a);
-            })
-            .catch(error => {
+            downloadJobResults(uploadJob.jobId).catch(error => {
                 console.error('Download failed:', error);
-                alert('Download failed. Please try again.');
+                alert('Download failed: ' + error.message);
             });
         });
         
@@ -458,6 +443,52 @@ document.getElementById('uploadForm').addEventListener('submit', async function(
     }
 });
 
+// Download the results of a completed job: the export returns the artifact of the file,
+// which is then downloaded by its ID. Failed requests are thrown by authenticatedFetch.
+async function downloadJobResults(jobId) {
+    const exportResponse = await authenticatedFetch(`/api/v1/influencers/jobs/${encodeURIComponent(jobId)}/export`, {
+        method: 'POST'
+    });
+    const artifact = await exportResponse.json();
+
+    // Files of an S3 storage are downloaded straight from the bucket with a presigned URL
+    let url = artifact.download_url;
+    if (!url) {
+        const response = await authenticatedFetch(`/api/v1/influencers/artifacts/${encodeURIComponent(artifact.id)}/download`);
+        url = window.URL.createObjectURL(await response.blob());
+    }
+
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = artifact.file_name;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    if (!artifact.download_url) {
+        window.URL.revokeObjectURL(url);
+    }
+}
+
+// Poll an analysis job until it completes or fails
+async function waitForJob(jobId) {
+    while (true) {
+        await new Promise(resolve => setTimeout(resolve, 2000));
+
+        const response = await authenticatedFetch(`/api/v1/influencers/jobs/${encodeURIComponent(jobId)}`);
+        const job = await response.json();
+
+        if (!response.ok) {
+            throw new Error(job.error || 'Failed to fetch job status');
+        }
+        if (job.status === 'failed' || job.status === 'cancelled') {
+            throw new Error(job.error || `Analysis ${job.status}`);
+        }
+        if (job.status === 'completed') {
+            return job;
+        }
+    }
+}
+
 // File upload functionality
 function setupFileUpload() {
     const fileInput = document.getElementById('fileInput');
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+	"golang.org/x/time/rate"
+)
+
+// DefaultPlatform is the key of the limit used by platforms without their own
+const DefaultPlatform = "default"
+
+// Limit bounds the requests sent to a platform
+type Limit struct {
+	RequestsPerMinute float64 // Rate of the requests, 0 means unlimited
+	MaxConcurrent     int     // Requests running at the same time, 0 means unlimited
+}
+
+// Limiter enforces a token bucket and a concurrency budget per platform.
+// A single Limiter must be shared by every job so that the limits are global to the process.
+type Limiter struct {
+	mutex     sync.Mutex
+	limits    map[string]Limit
+	platforms map[string]*platformLimiter
+}
+
+type platformLimiter struct {
+	tokens *rate.Limiter // nil when the rate is unlimited
+	slots  chan struct{} // nil when the concurrency is unlimited
+}
+
+// NewLimiter creates a new Limiter instance, limits are keyed by platform
+func NewLimiter(limits map[string]Limit) *Limiter {
+	return &Limiter{
+		limits:    limits,
+		platforms: make(map[string]*platformLimiter),
+	}
+}
+
+// Acquire waits until a request to the platform is allowed or ctx is done.
+// release must be called once the request finished to free its concurrency slot.
+func (l *Limiter) Acquire(ctx context.Context, platform string) (release func(), err error) {
+	p := l.platform(platform)
+
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release = func() {
+		if p.slots != nil {
+			<-p.slots
+		}
+	}
+
+	// The token is taken once a slot is free so that waiting requests don't burn the rate
+	if p.tokens != nil {
+		if err := p.tokens.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// Extractor wraps the extractor so that every extraction waits for the limit of its platform
+func (l *Limiter) Extractor(e extractor.StatisticExtractor) extractor.StatisticExtractor {
+	return &limitedExtractor{StatisticExtractor: e, limiter: l}
+}
+
+func (l *Limiter) platform(platform string) *platformLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if p, ok := l.platforms[platform]; ok {
+		return p
+	}
+
+	limit, ok := l.limits[platform]
+	if !ok {
+		limit = l.limits[DefaultPlatform]
+	}
+	p := &platformLimiter{}
+	if limit.RequestsPerMinute > 0 {
+		p.tokens = rate.NewLimiter(rate.Every(time.Duration(float64(time.Minute)/limit.RequestsPerMinute)), 1)
+	}
+	if limit.MaxConcurrent > 0 {
+		p.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	l.platforms[platform] = p
+	return p
+}
+
+type limitedExtractor struct {
+	extractor.StatisticExtractor
+	limiter *Limiter
+}
+
+// Extract extracts channel information once the platform limit allows it
+func (le *limitedExtractor) Extract(ctx context.Context, link string) (extractor.ChannelInfo, error) {
+	release, err := le.limiter.Acquire(ctx, le.Name())
+	if err != nil {
+		return extractor.ChannelInfo{OriginalLink: link}, err
+	}
+	defer release()
+	return le.StatisticExtractor.Extract(ctx, link)
+}
+
+var _ extractor.StatisticExtractor = (*limitedExtractor)(nil)
@@ -1,41 +1,45 @@
 package ruregistration
 
 import (
-	"encoding/json"
+	"context"
 	"log"
-	"os/exec"
 	"sync/atomic"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
 )
 
+// checkTimeout bounds a single run of the registration script
+const checkTimeout = 90 * time.Second
+
 var pendingChecks int32
 
-// CheckRegistrationStatus checks if the given link is registered on the specified website
-func CheckRegistrationStatus(link string, semaphore chan struct{}) bool {
+// CheckRegistrationStatus checks if the given link is registered on the specified website.
+// The check is run by runner and aborted when ctx is done. Failures are reported with the extractor errors.
+func CheckRegistrationStatus(ctx context.Context, runner extractor.ScriptRunner, link string) (bool, error) {
 	atomic.AddInt32(&pendingChecks, 1)
 	log.Printf("Checking registration status for: %s (Pending checks: %d)", link, atomic.LoadInt32(&pendingChecks))
-	cmd := exec.Command("node", "scripts/ru-registration.js", link)
-	output, err := cmd.Output()
+	defer atomic.AddInt32(&pendingChecks, -1)
+
+	output, err := runner.Run(ctx, checkTimeout, "scripts/ru-registration.js", link)
 	if err != nil {
 		log.Printf("Error executing Puppeteer script: %v", err)
-		atomic.AddInt32(&pendingChecks, -1)
-		<-semaphore // Release semaphore
-		return false
+		return false, err
 	}
 
 	var result struct {
-		IsRegistered bool `json:"isRegistered"`
+		IsRegistered bool   `json:"isRegistered"`
+		Error        string `json:"error"`
 	}
-	if err := json.Unmarshal(output, &result); err != nil {
+	if err := extractor.DecodeScriptOutput(output, &result); err != nil {
 		log.Printf("Error parsing JSON (%s) output: %v", link, output)
-		atomic.AddInt32(&pendingChecks, -1)
-		<-semaphore // Release semaphore
-		return false
+		return false, err
+	}
+	if err := extractor.ScriptError(result.Error); err != nil {
+		log.Printf("Registration check for %s failed: %v", link, err)
+		return false, err
 	}
-	defer func() {
-		atomic.AddInt32(&pendingChecks, -1)
-		<-semaphore // Release semaphore
-		log.Printf("Finished checking registration status for: %s (Pending checks: %d) %v", link, atomic.LoadInt32(&pendingChecks), result)
-	}()
 
-	return result.IsRegistered
+	log.Printf("Finished checking registration status for: %s (Pending checks: %d) %v", link, atomic.LoadInt32(&pendingChecks)-1, result)
+	return result.IsRegistered, nil
 }
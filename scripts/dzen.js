@@ -0,0 +1,92 @@
+const puppeteer = require('puppeteer');
+
+// Array of user agents to rotate through
+const userAgents = [
+    'Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36',
+    'Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36',
+    'Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36'
+];
+
+// Function to get a random user agent
+function getRandomUserAgent() {
+    return userAgents[Math.floor(Math.random() * userAgents.length)];
+}
+
+// Function to add random delay
+function randomDelay(min = 1000, max = 3000) {
+    return new Promise(resolve => {
+        const delay = Math.floor(Math.random() * (max - min + 1)) + min;
+        setTimeout(resolve, delay);
+    });
+}
+
+async function scrapeDzen(url) {
+    const browser = await puppeteer.launch({
+        headless: true,
+        args: [
+            '--no-sandbox',
+            '--disable-setuid-sandbox',
+            '--disable-blink-features=AutomationControlled'
+        ]
+    });
+
+    const page = await browser.newPage();
+    let result = { channelName: 'Dzen Error', followersCount: 'N/A' };
+
+    try {
+        await page.setUserAgent(getRandomUserAgent());
+        await page.setViewport({ width: 1366, height: 768 });
+        // Counters are parsed in russian ("12,5 тыс. подписчиков")
+        await page.setExtraHTTPHeaders({ 'Accept-Language': 'ru-RU,ru;q=0.9' });
+
+        await randomDelay(500, 1500);
+
+        const response = await page.goto(url, { waitUntil: 'networkidle2', timeout: 30000 });
+        if (response && response.status() === 404) {
+            return { channelName: 'Dzen Error', followersCount: 'N/A', error: 'not_found' };
+        }
+
+        await randomDelay(1000, 2000);
+
+        result = await page.evaluate(() => {
+            const channelName = document.querySelector('meta[property="og:title"]')?.getAttribute('content')?.trim() || document.querySelector('h1')?.innerText.trim() || '';
+
+            // Channels show "12,5 тыс. подписчиков"
+            const followers = document.body.innerText.match(/\d[\d.,\u00a0 ]*(?:тыс\.?|млн|K|M)?[\u00a0 ]*(подписчик|subscribers|followers)/i);
+            if (!followers) {
+                return { channelName, followersCount: '', error: channelName ? 'parse_failure' : 'not_found' };
+            }
+            return { channelName, followersCount: followers[0].trim() };
+        });
+    } catch (error) {
+        result = { channelName: 'Dzen Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
+    } finally {
+        await browser.close();
+    }
+
+    return result;
+}
+
+// Main execution
+async function main() {
+    const url = process.argv[2];
+
+    if (!url) {
+        console.error('Please provide a Dzen URL');
+        process.exit(1);
+    }
+
+    try {
+        const result = await scrapeDzen(url);
+        console.log(JSON.stringify(result));
+    } catch (error) {
+        console.log(JSON.stringify({ channelName: 'Dzen Error', followersCount: 'N/A', error: 'script_error' }));
+    }
+}
+
+// Run if this script is executed directly
+if (require.main === module) {
+    main();
+}
+
+module.exports = { scrapeDzen };
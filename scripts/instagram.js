@@ -26,6 +26,10 @@ async function scrapeInstagram(url, igUsername, igPassword) {
 
         // Wait for login to complete
         await page.waitForNavigation({ waitUntil: 'networkidle2' });
+        if (page.url().includes('/accounts/login') || page.url().includes('/challenge')) {
+            await browser.close();
+            return { channelName: 'Instagram Error', followersCount: 'N/A', error: 'login_required' };
+        }
 
         // Navigate to the target Instagram page
         await page.goto(url, { waitUntil: 'networkidle2' });
@@ -34,16 +38,9 @@ async function scrapeInstagram(url, igUsername, igPassword) {
         const result = await page.evaluate(() => {
             let channelName = document.querySelector('meta[property="og:title"]')?.getAttribute('content').trim() || '';
             const followersText = document.querySelector('meta[name="description"]')?.getAttribute('content').trim() || '';
-            const followersMatch = followersText.match(/(\d+(?:\.\d+)?[KM]?) Followers/);
-            let followersCount = followersMatch ? followersMatch[1] : '';
-
-            // Convert followers count to a number
-            followersCount = followersCount.toUpperCase();
-            if (followersCount.includes('K')) {
-                followersCount = followersCount.replace('K', '000');
-            } else if (followersCount.includes('M')) {
-                followersCount = followersCount.replace('M', '000000');
-            }
+            const followersMatch = followersText.match(/([\d.,]+\s*[KMB]?) Followers/i);
+            // The raw text ("1,234", "1.5M") is converted by the Go extractor
+            const followersCount = followersMatch ? followersMatch[1] : '';
 
             // Extract the username from the channel name
             if (channelName.includes('•')) {
@@ -54,7 +51,15 @@ async function scrapeInstagram(url, igUsername, igPassword) {
                 channelName = channelName.replace('@', '');
             }
 
-            return { channelName, followersCount };
+            const pageText = document.body.innerText;
+            let error = '';
+            if (!followersCount && pageText.includes("Sorry, this page isn't available")) {
+                error = 'not_found';
+            } else if (!followersCount && pageText.includes('This account is private')) {
+                error = 'private';
+            }
+
+            return { channelName, followersCount, error };
         });
 
         await browser.close();
@@ -63,28 +68,33 @@ async function scrapeInstagram(url, igUsername, igPassword) {
     } catch (error) {
         console.error('Instagram scraping error:', error.message);
         await browser.close();
-        return { channelName: 'Instagram Error', followersCount: 'N/A' };
+        return { channelName: 'Instagram Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
     }
 }
 
-// Get the URL and credentials from command line arguments
-const url = process.argv[2];
-const igUsername = process.argv[3];
-const igPassword = process.argv[4];
+// Run if this script is executed directly
+if (require.main === module) {
+    // Get the URL and credentials from command line arguments
+    const url = process.argv[2];
+    const igUsername = process.argv[3];
+    const igPassword = process.argv[4];
 
-if (!url) {
-    console.error('Please provide an Instagram URL');
-    process.exit(1);
-}
+    if (!url) {
+        console.error('Please provide an Instagram URL');
+        process.exit(1);
+    }
+
+    if (!igUsername || !igPassword) {
+        console.error('Please provide Instagram username and password');
+        process.exit(1);
+    }
 
-if (!igUsername || !igPassword) {
-    console.error('Please provide Instagram username and password');
-    process.exit(1);
+    scrapeInstagram(url, igUsername, igPassword).then(result => {
+        console.log(JSON.stringify(result));
+    }).catch(error => {
+        console.error('Error:', error);
+        console.log(JSON.stringify({ channelName: 'Instagram Script Error', followersCount: 'N/A', error: 'script_error' }));
+    });
 }
 
-scrapeInstagram(url, igUsername, igPassword).then(result => {
-    console.log(JSON.stringify(result));
-}).catch(error => {
-    console.error('Error:', error);
-    console.log(JSON.stringify({ channelName: 'Instagram Script Error', followersCount: 'N/A' }));
-});
+module.exports = { scrapeInstagram };
@@ -25,7 +25,7 @@ async function checkRegistrationStatus(url) {
     await page.click('#tabpanel-link > lib-button > div > button');
   } catch (error) {
     await browser.close();
-    return false;
+    throw error;
   }
 
   // Wait for the result
@@ -43,12 +43,16 @@ async function checkRegistrationStatus(url) {
 }
 
 
-const url = process.argv[2];
+// Run if this script is executed directly
+if (require.main === module) {
+  const url = process.argv[2];
+
+  checkRegistrationStatus(url).then(isRegistered => {
+    console.log(JSON.stringify({ isRegistered }));
+  }).catch(error => {
+    console.error('Registration check error:', error.message);
+    console.log(JSON.stringify({ isRegistered: false, error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' }));
+  });
+}
 
-checkRegistrationStatus(url).then(isRegistered => {
-  console.log(JSON.stringify({ isRegistered }));
-}).catch(error => {
-  // save error to file
-  fs.writeFileSync('error.txt', error);
-  console.log(JSON.stringify({ isRegistered }));
-});
+module.exports = { checkRegistrationStatus };
@@ -57,10 +57,13 @@ async function scrapeRutube(url) {
         // Add random delay before navigation
         await randomDelay(500, 1500);
         
-        await page.goto(url, { 
+        const response = await page.goto(url, { 
             waitUntil: 'networkidle2',
             timeout: 30000 
         });
+        if (response && response.status() === 404) {
+            return { channelName: 'Rutube Error', followersCount: 'N/A', error: 'not_found' };
+        }
         
         // Wait for page to load
         await randomDelay(2000, 4000);
@@ -116,10 +119,9 @@ async function scrapeRutube(url) {
             
             const followersText = await page.$eval(followersSelector, el => el.textContent.trim());
             
-            // Clean up the followers string to remove non-digit characters
-            const cleanedFollowers = followersText.replace(/\D/g, '');
-            if (cleanedFollowers) {
-                followersCount = cleanedFollowers;
+            // The raw text ("12,5 тыс. подписчиков") is converted by the Go extractor
+            if (/\d/.test(followersText)) {
+                followersCount = followersText;
             }
         } catch (error) {
             // Try alternative selectors for followers
@@ -140,12 +142,9 @@ async function scrapeRutube(url) {
                     const elements = await page.$$(selector);
                     for (const element of elements) {
                         const text = await page.evaluate(el => el.textContent.trim(), element);
-                        if (text) {
-                            const cleanedText = text.replace(/\D/g, '');
-                            if (cleanedText && cleanedText !== '') {
-                                followersCount = cleanedText;
-                                break;
-                            }
+                        if (text && /\d/.test(text)) {
+                            followersCount = text;
+                            break;
                         }
                     }
                     if (followersCount !== '0') break;
@@ -161,7 +160,7 @@ async function scrapeRutube(url) {
         };
         
     } catch (error) {
-        result = { channelName: 'Rutube Error', followersCount: 'N/A' };
+        result = { channelName: 'Rutube Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
     } finally {
         await browser.close();
     }
@@ -181,7 +180,7 @@ async function main() {
         const result = await scrapeRutube(url);
         console.log(JSON.stringify(result));
     } catch (error) {
-        console.log(JSON.stringify({ channelName: 'Rutube Error', followersCount: 'N/A' }));
+        console.log(JSON.stringify({ channelName: 'Rutube Error', followersCount: 'N/A', error: 'script_error' }));
     }
 }
 
@@ -65,6 +65,11 @@ async function scrapeTelegram(url) {
         // Wait for page to load
         await randomDelay(2000, 4000);
         
+        // Pages of unknown usernames have no title block
+        if (!(await page.$('.tgme_page_title'))) {
+            return { channelName: 'Telegram Error', followersCount: 'N/A', error: 'not_found' };
+        }
+        
         // Try to extract channel name
         let channelName = 'Unknown';
         try {
@@ -105,10 +110,8 @@ async function scrapeTelegram(url) {
             
             // Look for subscriber/member/follower count
             if (extraText.includes('subscriber') || extraText.includes('member') || extraText.includes('follower')) {
-                const matches = extraText.match(/[\d\s]+/);
-                if (matches && matches[0]) {
-                    followersCount = matches[0].replace(/\s/g, '');
-                }
+                // The raw text ("12 345 subscribers") is converted by the Go extractor
+                followersCount = extraText;
             }
         } catch (error) {
             // Try alternative selectors for followers
@@ -129,11 +132,8 @@ async function scrapeTelegram(url) {
                     for (const element of elements) {
                         const text = await page.evaluate(el => el.textContent.trim(), element);
                         if (text && (text.includes('subscriber') || text.includes('member') || text.includes('follower'))) {
-                            const matches = text.match(/[\d\s]+/);
-                            if (matches && matches[0]) {
-                                followersCount = matches[0].replace(/\s/g, '');
-                                break;
-                            }
+                            followersCount = text;
+                            break;
                         }
                     }
                     if (followersCount !== '0') break;
@@ -149,7 +149,7 @@ async function scrapeTelegram(url) {
         };
         
     } catch (error) {
-        result = { channelName: 'Telegram Error', followersCount: 'N/A' };
+        result = { channelName: 'Telegram Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
     } finally {
         await browser.close();
     }
@@ -169,7 +169,7 @@ async function main() {
         const result = await scrapeTelegram(url);
         console.log(JSON.stringify(result));
     } catch (error) {
-        console.log(JSON.stringify({ channelName: 'Telegram Error', followersCount: 'N/A' }));
+        console.log(JSON.stringify({ channelName: 'Telegram Error', followersCount: 'N/A', error: 'script_error' }));
     }
 }
 
@@ -157,12 +157,13 @@ async function scrapeTikTok(url) {
         
         // If still no data, return error
         if (!result.channelName && !result.followersCount) {
-            result = { channelName: 'TikTok Scraping Failed', followersCount: 'N/A' };
+            const notFound = await page.evaluate(() => document.body.innerText.includes("Couldn't find this account"));
+            result = { channelName: 'TikTok Scraping Failed', followersCount: 'N/A', error: notFound ? 'not_found' : 'parse_failure' };
         }
         
     } catch (error) {
         console.error('TikTok scraping error:', error.message);
-        result = { channelName: 'TikTok Error', followersCount: 'N/A' };
+        result = { channelName: 'TikTok Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
     } finally {
         await browser.close();
     }
@@ -170,17 +171,22 @@ async function scrapeTikTok(url) {
     return result;
 }
 
-// Get the URL from command line arguments
-const url = process.argv[2];
+// Run if this script is executed directly
+if (require.main === module) {
+    // Get the URL from command line arguments
+    const url = process.argv[2];
 
-if (!url) {
-    console.error('Please provide a TikTok URL');
-    process.exit(1);
+    if (!url) {
+        console.error('Please provide a TikTok URL');
+        process.exit(1);
+    }
+
+    scrapeTikTok(url).then(result => {
+        console.log(JSON.stringify(result));
+    }).catch(error => {
+        console.error('Error:', error);
+        console.log(JSON.stringify({ channelName: 'Script Error', followersCount: 'N/A', error: 'script_error' }));
+    });
 }
 
-scrapeTikTok(url).then(result => {
-    console.log(JSON.stringify(result));
-}).catch(error => {
-    console.error('Error:', error);
-    console.log(JSON.stringify({ channelName: 'Script Error', followersCount: 'N/A' }));
-});
\ No newline at end of file
+module.exports = { scrapeTikTok };
@@ -75,7 +75,7 @@ async function scrapeVK(url) {
                 
                 if (challengeAttempts >= maxChallengeAttempts) {
                     await browser.close();
-                    return { channelName: 'Challenge Error - Max attempts reached', followersText: 'N/A' };
+                    return { channelName: 'Challenge Error - Max attempts reached', followersText: 'N/A', error: 'rate_limited' };
                 }
                 
                 // Wait before retrying
@@ -90,7 +90,7 @@ async function scrapeVK(url) {
     // If we've reached max attempts and still on challenge page
     if (challengeAttempts >= maxChallengeAttempts && page.url().includes('/challenge.html')) {
         await browser.close();
-        return { channelName: 'Challenge Error - Too many challenges', followersText: 'N/A' };
+        return { channelName: 'Challenge Error - Too many challenges', followersText: 'N/A', error: 'rate_limited' };
     }
 
     
@@ -98,7 +98,23 @@ async function scrapeVK(url) {
     const finalUrl = page.url();
     if (finalUrl.includes('/challenge.html')) {
         await browser.close();
-        return { channelName: 'Still on challenge page', followersText: 'N/A' };
+        return { channelName: 'Still on challenge page', followersText: 'N/A', error: 'rate_limited' };
+    }
+
+    // Deleted pages and closed communities have no public followers count
+    const pageState = await page.evaluate(() => {
+        const text = document.body.innerText;
+        if (text.includes('Страница удалена либо ещё не создана') || text.includes('This page has either been deleted or not been created yet')) {
+            return 'not_found';
+        }
+        if (text.includes('Это закрытое сообщество') || text.includes('This is a closed community') || text.includes('This is a private community')) {
+            return 'private';
+        }
+        return '';
+    });
+    if (pageState) {
+        await browser.close();
+        return { channelName: 'VK Error', followersText: 'N/A', error: pageState };
     }
 
     // Extract channel name and followers count with retry logic
@@ -133,24 +149,29 @@ async function scrapeVK(url) {
     
     // If still no data after all attempts, return error
     if (!result.channelName && !result.followersText) {
-        result = { channelName: 'Scraping Failed', followersText: 'N/A' };
+        result = { channelName: 'Scraping Failed', followersText: 'N/A', error: 'parse_failure' };
     }
 
     await browser.close();
     return result;
 }
 
-// Get the URL from command line arguments
-const url = process.argv[2];
+// Run if this script is executed directly
+if (require.main === module) {
+    // Get the URL from command line arguments
+    const url = process.argv[2];
+
+    if (!url) {
+        console.error('Please provide a VK URL');
+        process.exit(1);
+    }
 
-if (!url) {
-    console.error('Please provide a VK URL');
-    process.exit(1);
+    scrapeVK(url).then(result => {
+        console.log(JSON.stringify(result));
+    }).catch(error => {
+        console.error('Error:', error);
+        console.log(JSON.stringify({ channelName: 'VK Script Error', followersText: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' }));
+    });
 }
 
-scrapeVK(url).then(result => {
-    console.log(JSON.stringify(result));
-}).catch(error => {
-    console.error('Error:', error);
-    console.log(JSON.stringify({ channelName: 'VK Script Error', followersText: 'N/A' }));
-});
+module.exports = { scrapeVK };
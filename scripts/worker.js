@@ -0,0 +1,173 @@
+// Long-lived scraping worker driven by the Go worker pool.
+//
+// It reads one JSON request per line on stdin and writes one JSON response per line on stdout:
+//   {"id": 1, "script": "telegram", "args": ["https://t.me/channel"]}
+//   {"id": 1, "result": {"channelName": "...", "followersCount": "..."}}
+//   {"id": 2, "type": "ping"}
+//   {"id": 2, "ok": true}
+// A single browser is shared by every request and its pages are reused between requests.
+const readline = require('readline');
+const puppeteer = require('puppeteer');
+
+// Maximum number of idle pages kept open for the next requests
+const MAX_IDLE_PAGES = 2;
+
+// stdout carries the protocol, the logs of the scripts go to stderr
+const protocolOut = process.stdout.write.bind(process.stdout);
+console.log = console.error;
+console.info = console.error;
+
+const launch = puppeteer.launch.bind(puppeteer);
+let browserPromise = null;
+const idlePages = [];
+
+function sharedBrowser() {
+    if (!browserPromise) {
+        browserPromise = launch({
+            headless: true,
+            args: [
+                '--no-sandbox',
+                '--disable-setuid-sandbox',
+                '--disable-dev-shm-usage',
+                '--disable-blink-features=AutomationControlled',
+                '--disable-gpu'
+            ]
+        }).then(browser => {
+            browser.on('disconnected', () => {
+                browserPromise = null;
+                idlePages.length = 0;
+            });
+            return browser;
+        }).catch(error => {
+            browserPromise = null;
+            throw error;
+        });
+    }
+    return browserPromise;
+}
+
+// resetPage clears what a script left on the page before it is handed to the next one
+async function resetPage(page) {
+    page.removeAllListeners();
+    await page.setRequestInterception(false);
+    await page.goto('about:blank');
+}
+
+// Scripts call puppeteer.launch() and browser.close() for every link, inside the worker
+// they get a handle on the shared browser whose close() only gives their pages back.
+puppeteer.launch = async () => {
+    const browser = await sharedBrowser();
+    const pages = [];
+    const handle = {
+        async newPage() {
+            let page = idlePages.pop();
+            if (!page || page.isClosed()) {
+                page = await browser.newPage();
+            }
+            pages.push(page);
+            return page;
+        },
+        async close() {
+            for (const page of pages.splice(0)) {
+                if (page.isClosed()) {
+                    continue;
+                }
+                try {
+                    if (idlePages.length < MAX_IDLE_PAGES) {
+                        await resetPage(page);
+                        idlePages.push(page);
+                    } else {
+                        await page.close();
+                    }
+                } catch (error) {
+                    await page.close().catch(() => {});
+                }
+            }
+        }
+    };
+    return new Proxy(handle, {
+        get(target, property) {
+            if (property in target) {
+                return target[property];
+            }
+            const value = browser[property];
+            return typeof value === 'function' ? value.bind(browser) : value;
+        }
+    });
+};
+
+const { scrapeTelegram } = require('./telegram');
+const { scrapeRutube } = require('./rutube');
+const { scrapeVK } = require('./vk');
+const { scrapeInstagram } = require('./instagram');
+const { scrapeTikTok } = require('./tiktok');
+const { scrapeYouTube } = require('./youtube');
+const { scrapeOK } = require('./ok');
+const { scrapeDzen } = require('./dzen');
+const { checkRegistrationStatus } = require('./ru-registration');
+
+// Handlers are keyed by the name of the script they replace
+const handlers = {
+    'telegram': args => scrapeTelegram(args[0]),
+    'rutube': args => scrapeRutube(args[0]),
+    'vk': args => scrapeVK(args[0]),
+    'instagram': args => scrapeInstagram(args[0], args[1], args[2]),
+    'tiktok': args => scrapeTikTok(args[0]),
+    'youtube': args => scrapeYouTube(args[0]),
+    'ok': args => scrapeOK(args[0]),
+    'dzen': args => scrapeDzen(args[0]),
+    'ru-registration': async args => ({ isRegistered: await checkRegistrationStatus(args[0]) })
+};
+
+function respond(response) {
+    protocolOut(JSON.stringify(response) + '\n');
+}
+
+async function handle(request) {
+    if (request.type === 'ping') {
+        // The worker is healthy when its browser can be launched and is connected
+        const browser = await sharedBrowser();
+        if (!browser.isConnected()) {
+            throw new Error('browser disconnected');
+        }
+        return { id: request.id, ok: true };
+    }
+
+    const handler = handlers[request.script];
+    if (!handler) {
+        return { id: request.id, error: `unknown script ${request.script}` };
+    }
+    try {
+        return { id: request.id, result: await handler(request.args || []) };
+    } catch (error) {
+        console.error(`Error running ${request.script}:`, error);
+        return { id: request.id, result: { error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' } };
+    }
+}
+
+const input = readline.createInterface({ input: process.stdin });
+input.on('line', async line => {
+    let request;
+    try {
+        request = JSON.parse(line);
+    } catch (error) {
+        console.error('Invalid request:', line);
+        return;
+    }
+    try {
+        respond(await handle(request));
+    } catch (error) {
+        respond({ id: request.id, error: error.message });
+    }
+});
+
+// The pool closes stdin to stop the worker
+input.on('close', async () => {
+    if (browserPromise) {
+        const browser = await browserPromise.catch(() => null);
+        if (browser) {
+            await browser.close().catch(() => {});
+        }
+    }
+    process.exit(0);
+});
@@ -0,0 +1,137 @@
+const puppeteer = require('puppeteer');
+
+// Array of user agents to rotate through
+const userAgents = [
+    'Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36',
+    'Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36',
+    'Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36'
+];
+
+// Function to get a random user agent
+function getRandomUserAgent() {
+    return userAgents[Math.floor(Math.random() * userAgents.length)];
+}
+
+// Function to add random delay
+function randomDelay(min = 1000, max = 3000) {
+    return new Promise(resolve => {
+        const delay = Math.floor(Math.random() * (max - min + 1)) + min;
+        setTimeout(resolve, delay);
+    });
+}
+
+// acceptConsent clicks through the cookie consent page shown in some regions
+async function acceptConsent(page) {
+    if (!page.url().includes('consent.')) {
+        return;
+    }
+    const button = await page.$('form[action*="consent"] button');
+    if (button) {
+        await Promise.all([
+            page.waitForNavigation({ waitUntil: 'networkidle2', timeout: 30000 }),
+            button.click()
+        ]);
+    }
+}
+
+async function scrapeYouTube(url) {
+    const browser = await puppeteer.launch({
+        headless: true,
+        args: [
+            '--no-sandbox',
+            '--disable-setuid-sandbox',
+            '--disable-blink-features=AutomationControlled'
+        ]
+    });
+
+    const page = await browser.newPage();
+    let result = { channelName: 'YouTube Error', followersCount: 'N/A' };
+
+    try {
+        await page.setUserAgent(getRandomUserAgent());
+        await page.setViewport({ width: 1366, height: 768 });
+        // Counters are parsed in english ("1.2M subscribers")
+        await page.setExtraHTTPHeaders({ 'Accept-Language': 'en-US,en;q=0.9' });
+
+        await randomDelay(500, 1500);
+
+        let response = await page.goto(url, { waitUntil: 'networkidle2', timeout: 30000 });
+        await acceptConsent(page);
+        if (response && response.status() === 404) {
+            return { channelName: 'YouTube Error', followersCount: 'N/A', error: 'not_found' };
+        }
+
+        // Video links (youtu.be/ID, /watch?v=ID) are resolved to the channel of their author
+        if (!/\/(@|channel\/|c\/|user\/)/.test(page.url())) {
+            const channelUrl = await page.evaluate(() => {
+                const author = document.querySelector('span[itemprop="author"] link[itemprop="url"]');
+                if (author) {
+                    return author.getAttribute('href');
+                }
+                const owner = document.querySelector('ytd-video-owner-renderer a[href]');
+                return owner ? owner.href : '';
+            });
+            if (!channelUrl) {
+                return { channelName: 'YouTube Error', followersCount: 'N/A', error: 'not_found' };
+            }
+            response = await page.goto(channelUrl, { waitUntil: 'networkidle2', timeout: 30000 });
+            if (response && response.status() === 404) {
+                return { channelName: 'YouTube Error', followersCount: 'N/A', error: 'not_found' };
+            }
+        }
+
+        await randomDelay(1000, 2000);
+
+        // The channel page identifies the channel of video links
+        const channelLink = page.url();
+        result = await page.evaluate(() => {
+            const channelName = document.querySelector('meta[property="og:title"]')?.getAttribute('content')?.trim() || '';
+
+            // The header reads "@handle • 1.2M subscribers • 345 videos", the counters are
+            // also present in the initial data of the page when the header is not rendered
+            const text = document.body.innerText + '\n' + Array.from(document.scripts).map(s => s.textContent).join('\n');
+            const subscribers = text.match(/(\d[\d.,\u00a0 ]*[KMB]?)[\u00a0 ]+subscribers?/i);
+            const videos = text.match(/(\d[\d.,\u00a0 ]*[KMB]?)[\u00a0 ]+videos?/i);
+
+            if (!subscribers) {
+                return { channelName, followersCount: '', error: channelName ? 'parse_failure' : 'not_found' };
+            }
+            return {
+                channelName,
+                followersCount: subscribers[0].trim(),
+                videosCount: videos ? videos[0].trim() : ''
+            };
+        });
+        result.channelLink = channelLink;
+    } catch (error) {
+        result = { channelName: 'YouTube Error', followersCount: 'N/A', error: error.name === 'TimeoutError' ? 'timeout' : 'script_error' };
+    } finally {
+        await browser.close();
+    }
+
+    return result;
+}
+
+// Main execution
+async function main() {
+    const url = process.argv[2];
+
+    if (!url) {
+        console.error('Please provide a YouTube URL');
+        process.exit(1);
+    }
+
+    try {
+        const result = await scrapeYouTube(url);
+        console.log(JSON.stringify(result));
+    } catch (error) {
+        console.log(JSON.stringify({ channelName: 'YouTube Error', followersCount: 'N/A', error: 'script_error' }));
+    }
+}
+
+// Run if this script is executed directly
+if (require.main === module) {
+    main();
+}
+
+module.exports = { scrapeYouTube };
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalStorage keeps the result files in a directory of the server
+type LocalStorage struct {
+	dir string
+}
+
+var _ Storage = (*LocalStorage)(nil)
+
+// NewLocalStorage creates the directory of the files if it does not exist
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("storage directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create the storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save moves the file into the directory, files already in place are kept as they are
+func (ls *LocalStorage) Save(ctx context.Context, key string, filePath string) error {
+	target, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(filePath) == target {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(filePath, target); err == nil {
+		return nil
+	}
+
+	// The file is on another device, copy it
+	if err := copyFile(filePath, target); err != nil {
+		return err
+	}
+	return os.Remove(filePath)
+}
+
+// Exists reports whether the file of the key is in the directory
+func (ls *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	target, err := ls.path(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(target); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Open opens the file of the key, ErrNotFound when it is not in the directory
+func (ls *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// PresignedURL returns ErrPresignUnsupported, the local files are streamed by the server
+func (ls *LocalStorage) PresignedURL(ctx context.Context, key string, fileName string, expiry time.Duration) (string, error) {
+	return "", ErrPresignUnsupported
+}
+
+// Delete removes the file of the key and the directories it leaves empty, missing files are not an error
+func (ls *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// Remove the directories of the key left empty, non empty ones fail and are kept
+	for dir := filepath.Dir(target); dir != filepath.Clean(ls.dir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// path resolves the key inside the directory
+func (ls *LocalStorage) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ls.dir, filepath.FromSlash(key)), nil
+}
+
+func copyFile(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
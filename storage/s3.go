@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage keeps the result files in a bucket of an S3 compatible service (AWS S3, MinIO, ...)
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+var _ Storage = (*S3Storage)(nil)
+
+// NewS3Storage connects to the service and creates the bucket if it does not exist
+func NewS3Storage(cfg Config) (*S3Storage, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket cannot be empty")
+	}
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the S3 client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the bucket %s: %w", cfg.S3Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.S3Bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+			return nil, fmt.Errorf("failed to create the bucket %s: %w", cfg.S3Bucket, err)
+		}
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: cfg.S3Bucket,
+	}, nil
+}
+
+// Save uploads the file and removes the local copy
+func (ss *S3Storage) Save(ctx context.Context, key string, filePath string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if _, err := ss.client.FPutObject(ctx, ss.bucket, key, filePath, minio.PutObjectOptions{ContentType: contentType}); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return os.Remove(filePath)
+}
+
+// Exists reports whether the bucket has an object under the key
+func (ss *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := ss.client.StatObject(ctx, ss.bucket, key, minio.StatObjectOptions{}); err != nil {
+		if isNoSuchKey(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Open streams the object of the bucket, ErrNotFound when there is none
+func (ss *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	object, err := ss.client.GetObject(ctx, ss.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject is lazy, the missing objects are only reported once they are read
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if isNoSuchKey(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+// PresignedURL returns a URL of the bucket downloading the object as an attachment named fileName
+func (ss *S3Storage) PresignedURL(ctx context.Context, key string, fileName string, expiry time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if expiry <= 0 {
+		return "", ErrPresignUnsupported
+	}
+	params := url.Values{}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	u, err := ss.client.PresignedGetObject(ctx, ss.bucket, key, expiry, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// Delete removes the object from the bucket, missing objects are not an error
+func (ss *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	// Removing a missing object succeeds
+	return ss.client.RemoveObject(ctx, ss.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func isNoSuchKey(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// s3TestConfig configures the S3 compatible service of the integration tests (MinIO for example).
+// The test is skipped unless S3_TEST_ENDPOINT is set.
+func s3TestConfig(t *testing.T) Config {
+	t.Helper()
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "results-test"
+	}
+	return Config{
+		Backend:     BackendS3,
+		S3Endpoint:  endpoint,
+		S3AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		S3Bucket:    bucket,
+		S3Region:    os.Getenv("S3_TEST_REGION"),
+		S3UseSSL:    os.Getenv("S3_TEST_USE_SSL") == "true",
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	ss, err := NewS3Storage(s3TestConfig(t))
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	ctx := context.Background()
+	// Every run has its own keys, so runs sharing the bucket don't see each other
+	key := fmt.Sprintf("test-%d/channels_followers.csv", time.Now().UnixNano())
+	missing := key + ".missing"
+	t.Cleanup(func() {
+		if err := ss.Delete(context.Background(), key); err != nil {
+			t.Logf("Failed to delete %s: %v", key, err)
+		}
+	})
+
+	source := filepath.Join(t.TempDir(), "work.csv")
+	if err := os.WriteFile(source, []byte("a,b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Save(ctx, key, source); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("Save kept the source file: %v", err)
+	}
+
+	tests := []struct {
+		key        string
+		wantExists bool
+	}{
+		{key: key, wantExists: true},
+		{key: missing, wantExists: false},
+	}
+	for _, tt := range tests {
+		if exists, err := ss.Exists(ctx, tt.key); err != nil || exists != tt.wantExists {
+			t.Errorf("Exists(%q) = %v, %v, want %v", tt.key, exists, err, tt.wantExists)
+		}
+	}
+
+	file, err := ss.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(data) != "a,b\n" {
+		t.Fatalf("Open read %q, %v", data, err)
+	}
+	if _, err := ss.Open(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a missing key error = %v, want ErrNotFound", err)
+	}
+
+	// The presigned URL downloads the object as an attachment without credentials
+	presigned, err := ss.PresignedURL(ctx, key, "channels_followers.csv", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+	resp, err := http.Get(presigned)
+	if err != nil {
+		t.Fatalf("GET of the presigned URL: %v", err)
+	}
+	data, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || string(data) != "a,b\n" {
+		t.Fatalf("GET of the presigned URL = %d %q, %v", resp.StatusCode, data, err)
+	}
+	wantDisposition := mime.FormatMediaType("attachment", map[string]string{"filename": "channels_followers.csv"})
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != wantDisposition {
+		t.Errorf("Content-Disposition = %q, want %q", disposition, wantDisposition)
+	}
+	if _, err := ss.PresignedURL(ctx, key, "channels_followers.csv", 0); !errors.Is(err, ErrPresignUnsupported) {
+		t.Errorf("PresignedURL without expiry error = %v, want ErrPresignUnsupported", err)
+	}
+
+	if err := ss.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, err := ss.Exists(ctx, key); err != nil || exists {
+		t.Errorf("Exists after Delete = %v, %v, want false", exists, err)
+	}
+	if _, err := ss.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete error = %v, want ErrNotFound", err)
+	}
+	if err := ss.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+
+	for _, key := range []string{"../.env", "a/../../.env", "/etc/passwd"} {
+		if _, err := ss.Exists(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Exists(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if _, err := ss.Open(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Open(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if err := ss.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound           = errors.New("object not found")
+	ErrInvalidKey         = errors.New("invalid storage key")
+	ErrPresignUnsupported = errors.New("presigned URLs are not supported")
+)
+
+// Backends of the result files
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Storage keeps the result files under keys. Keys are slash separated relative paths, they never leave the storage.
+type Storage interface {
+	// Save stores the local file under the key, the local file is consumed
+	Save(ctx context.Context, key string, filePath string) error
+	// Exists reports whether an object is stored under the key
+	Exists(ctx context.Context, key string) (bool, error)
+	// Open streams the object, ErrNotFound when there is none
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// PresignedURL returns a temporary URL downloading the object as fileName,
+	// ErrPresignUnsupported when the object has to be streamed through Open
+	PresignedURL(ctx context.Context, key string, fileName string, expiry time.Duration) (string, error)
+	// Delete removes the object, missing objects are not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures the backend of the result files
+type Config struct {
+	Backend string // local or s3, local when empty
+	Dir     string // Directory of the local backend
+
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3Bucket    string
+	S3Region    string
+	S3UseSSL    bool
+}
+
+// New creates the storage of the configured backend
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalStorage(cfg.Dir)
+	case BackendS3:
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, use %s or %s", cfg.Backend, BackendLocal, BackendS3)
+	}
+}
+
+// cleanKey validates a key, absolute keys and keys escaping the storage are rejected
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, `\`) || path.IsAbs(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "report.xlsx", want: "report.xlsx"},
+		{key: "artifact/channels_followers.csv", want: "artifact/channels_followers.csv"},
+		{key: "artifact/./report.csv", want: "artifact/report.csv"},
+		{key: "a/b/../report.csv", want: "a/report.csv"},
+		{key: "", wantErr: true},
+		{key: ".", wantErr: true},
+		{key: "..", wantErr: true},
+		{key: "../.env", wantErr: true},
+		{key: "../../etc/passwd", wantErr: true},
+		{key: "a/../../.env", wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: `..\.env`, wantErr: true},
+		{key: `results\..\..\.env`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := cleanKey(tt.key)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidKey) {
+					t.Fatalf("cleanKey(%q) = %q, %v, want ErrInvalidKey", tt.key, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("cleanKey(%q) = %q, %v, want %q", tt.key, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorageRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "results")
+	ls, err := NewLocalStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A secret next to the storage directory must stay out of reach
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("JWT_SECRET=secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"../.env", "a/../../.env", filepath.Join(root, ".env")} {
+		if _, err := ls.Open(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Open(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if _, err := ls.Exists(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Exists(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if err := ls.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, ".env")); err != nil {
+		t.Fatalf("the file outside of the storage was touched: %v", err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	ls, err := NewLocalStorage(filepath.Join(dir, "results"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	source := filepath.Join(dir, "work.csv")
+	if err := os.WriteFile(source, []byte("a,b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	key := "artifact/channels_followers.csv"
+	if err := ls.Save(ctx, key, source); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("Save kept the source file: %v", err)
+	}
+	if exists, err := ls.Exists(ctx, key); err != nil || !exists {
+		t.Fatalf("Exists = %v, %v, want true", exists, err)
+	}
+
+	file, err := ls.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(data) != "a,b\n" {
+		t.Fatalf("Open read %q, %v", data, err)
+	}
+	if _, err := ls.PresignedURL(ctx, key, "channels_followers.csv", 0); !errors.Is(err, ErrPresignUnsupported) {
+		t.Errorf("PresignedURL error = %v, want ErrPresignUnsupported", err)
+	}
+
+	if err := ls.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := ls.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "results", "artifact")); !os.IsNotExist(err) {
+		t.Errorf("Delete kept the empty directory of the key: %v", err)
+	}
+	if err := ls.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package wiring builds the components shared by the commands from the configuration
+package wiring
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/config"
+	"github.com/solrac97gr/telegram-followers-checker/database"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/dzen"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/extractor"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/instagram"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/ok"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/rutube"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/telegram"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/tiktok"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/vk"
+	"github.com/solrac97gr/telegram-followers-checker/extractors/youtube"
+	"github.com/solrac97gr/telegram-followers-checker/filemanager"
+	"github.com/solrac97gr/telegram-followers-checker/ratelimit"
+)
+
+// NewInfluencerApp creates the InfluencerApp of the configuration with the extractors of every platform.
+// The returned function stops the browser workers, it must be called once the app is no longer used.
+func NewInfluencerApp(cfg *config.Config, repo database.InfluencerRepository) (*app.InfluencerApp, func()) {
+	// Scripts run on persistent browser workers unless BROWSER_WORKERS is 0
+	var runner extractor.ScriptRunner = extractor.ProcessRunner{}
+	closeRunner := func() {}
+	if cfg.BrowserWorkers > 0 {
+		pool := extractor.NewWorkerPool(extractor.WorkerPoolOptions{
+			Command:        strings.Fields(cfg.BrowserWorkerCommand),
+			Size:           cfg.BrowserWorkers,
+			HealthInterval: cfg.BrowserHealthInterval,
+		})
+		runner = pool
+		closeRunner = pool.Close
+	}
+	// Transient failures (timeouts, rate limits, crashes) are retried following the configured policies
+	retryPolicies := cfg.RetryPolicies()
+	// Every attempt waits for the rate and concurrency limits of its platform, shared by all the jobs
+	limiter := ratelimit.NewLimiter(cfg.Limits())
+
+	var telegramSource extractor.StatisticExtractor = telegram.NewTelegramExtractor(runner)
+	if cfg.TelegramHTTP {
+		telegramSource = telegram.NewTelegramHTTPExtractor(&http.Client{Timeout: 30 * time.Second}, telegramSource)
+	}
+	sources := []extractor.StatisticExtractor{
+		telegramSource,
+		rutube.NewRutubeExtractor(runner),
+		vk.NewVKExtractor(runner),
+		instagram.NewInstagramExtractor(runner),
+		tiktok.NewTikTokExtractor(runner),
+		youtube.NewYouTubeExtractor(runner),
+		ok.NewOKExtractor(runner),
+		dzen.NewDzenExtractor(runner),
+	}
+	extractors := make([]extractor.StatisticExtractor, 0, len(sources))
+	for _, source := range sources {
+		extractors = append(extractors, extractor.WithRetry(limiter.Extractor(source), retryPolicies.For(source.Name())))
+	}
+	// The registry decides which links are read from the files and which extractor handles them
+	registry := extractor.NewRegistry(extractors...)
+
+	influencers := app.NewInfluencerApp(repo, filemanager.NewFileManager(registry), registry, app.InfluencerAppOptions{
+		Limiter:             limiter,
+		Runner:              runner,
+		RegistrationRetry:   retryPolicies.For(app.RegistrationPlatform),
+		ExtractionWorkers:   cfg.ExtractionWorkers,
+		RegistrationWorkers: cfg.RegistrationWorkers,
+	})
+	return influencers, closeRunner
+}
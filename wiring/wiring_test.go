@@ -0,0 +1,77 @@
+package wiring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/solrac97gr/telegram-followers-checker/app"
+	"github.com/solrac97gr/telegram-followers-checker/config"
+	"github.com/solrac97gr/telegram-followers-checker/database"
+)
+
+// nopInfluencerRepository stores nothing, the links are only read
+type nopInfluencerRepository struct{}
+
+var _ database.InfluencerRepository = nopInfluencerRepository{}
+
+func (nopInfluencerRepository) SaveInfluencerAnalysis(influencer *database.InfluencerAnalysis) error {
+	return nil
+}
+
+func (nopInfluencerRepository) GetInfluencerAnalysisByLink(link string) (*database.InfluencerAnalysis, error) {
+	return nil, errors.New("not found")
+}
+
+func (nopInfluencerRepository) GetInfluencerAnalysisByChannelKey(channelKey string) (*database.InfluencerAnalysis, error) {
+	return nil, errors.New("not found")
+}
+
+func (nopInfluencerRepository) GetUserInfluencerAnalysisByChannelKey(userID string, channelKey string) (*database.InfluencerAnalysis, error) {
+	return nil, errors.New("not found")
+}
+
+func (nopInfluencerRepository) SaveReusedAnalysis(analysis *database.InfluencerAnalysis) error {
+	return nil
+}
+
+func (nopInfluencerRepository) DeleteExpiredAnalyses() error {
+	return nil
+}
+
+func (nopInfluencerRepository) SearchInfluencerAnalyses(query database.AnalysisQuery) (database.AnalysisPage, error) {
+	return database.AnalysisPage{}, nil
+}
+
+func TestNewInfluencerAppRegistersEveryPlatform(t *testing.T) {
+	for _, telegramHTTP := range []bool{false, true} {
+		influencers, closeRunner := NewInfluencerApp(&config.Config{TelegramHTTP: telegramHTTP}, nopInfluencerRepository{})
+		defer closeRunner()
+
+		tests := []struct {
+			link      string
+			supported bool
+		}{
+			{link: "https://t.me/durov", supported: true},
+			{link: "https://rutube.ru/channel/123", supported: true},
+			{link: "https://vk.com/durov", supported: true},
+			{link: "https://instagram.com/durov", supported: true},
+			{link: "https://tiktok.com/@durov", supported: true},
+			{link: "https://youtube.com/@durov", supported: true},
+			{link: "https://ok.ru/group/123", supported: true},
+			{link: "https://dzen.ru/durov", supported: true},
+			{link: "https://example.com/durov", supported: false},
+		}
+		for _, tt := range tests {
+			input := filepath.Join(t.TempDir(), "links.csv")
+			if err := os.WriteFile(input, []byte(tt.link+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := influencers.ValidateInput(input, app.RunOptions{})
+			if supported := err == nil; supported != tt.supported {
+				t.Errorf("telegram http %v, %s: supported = %v (%v), want %v", telegramHTTP, tt.link, supported, err, tt.supported)
+			}
+		}
+	}
+}